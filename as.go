@@ -0,0 +1,43 @@
+package value
+
+// As converts t to a value of type T with Value.ConvTo,
+// T can be any type ConvTo supports, like int8, time.Duration or a struct.
+func As[T any](v *Value) (T, error) {
+	return AsWithOptions[T](v, ConvOptions{})
+}
+
+// AsWithOptions same as As with options of Value.ConvToWithOptions.
+func AsWithOptions[T any](v *Value, opts ConvOptions) (T, error) {
+	var x T
+	err := v.ConvToWithOptions(&x, opts)
+	return x, err
+}
+
+// MustAs must api for As
+func MustAs[T any](v *Value) T {
+	x, err := As[T](v)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// GetAs gets the value of path like Value.GetPath, and converts it to T like As.
+func GetAs[T any](v *Value, path string) (T, error) {
+	val, err := v.GetPath(path)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return As[T](val)
+}
+
+// SliceOf converts t to a slice of T like As, t can be a slice or an array.
+func SliceOf[T any](v *Value) ([]T, error) {
+	return As[[]T](v)
+}
+
+// MapOf converts t to a map of K to V like As, t can be a map or a struct.
+func MapOf[K comparable, V any](v *Value) (map[K]V, error) {
+	return As[map[K]V](v)
+}
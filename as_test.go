@@ -0,0 +1,68 @@
+package value
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("As", func() {
+	type server struct {
+		Name    string
+		Timeout time.Duration
+	}
+	x := map[string]interface{}{
+		"port":    "8080",
+		"timeout": "30s",
+		"servers": []interface{}{
+			map[string]interface{}{"name": "a", "timeout": "1s"},
+		},
+		"weights": map[string]interface{}{"a": 1, "b": 2.0},
+	}
+
+	Specify("As() and MustAs()", func() {
+		v := New(x)
+		s, err := As[server](v.MustGetPath("servers[0]"))
+		Expect(err).Should(BeNil())
+		Expect(s).Should(Equal(server{"a", time.Second}))
+
+		Expect(MustAs[int8](New(uint64(1)))).Should(Equal(int8(1)))
+		Expect(MustAs[time.Duration](v.MustGetPath("timeout"))).Should(Equal(30 * time.Second))
+
+		_, err = As[int8](New(300))
+		Expect(err).ShouldNot(BeNil())
+		Expect(func() { MustAs[int8](New(300)) }).Should(Panic())
+
+		b, err := AsWithOptions[bool](New("yes"), ConvOptions{WeaklyTyped: true})
+		Expect(err).Should(BeNil())
+		Expect(b).Should(BeTrue())
+	})
+	Specify("GetAs()", func() {
+		v := New(x)
+		port, err := GetAs[uint16](v, "port")
+		Expect(err).Should(BeNil())
+		Expect(port).Should(Equal(uint16(8080)))
+
+		_, err = GetAs[int](v, "none")
+		Expect(err).Should(BeAssignableToTypeOf((*ErrPath)(nil)))
+	})
+	Specify("SliceOf() and MapOf()", func() {
+		v := New(x)
+		servers, err := SliceOf[server](v.MustGetPath("servers"))
+		Expect(err).Should(BeNil())
+		Expect(servers).Should(Equal([]server{{"a", time.Second}}))
+
+		ints, err := SliceOf[int](New([2]float64{1, 2}))
+		Expect(err).Should(BeNil())
+		Expect(ints).Should(Equal([]int{1, 2}))
+
+		weights, err := MapOf[string, float32](v.MustGetPath("weights"))
+		Expect(err).Should(BeNil())
+		Expect(weights).Should(Equal(map[string]float32{"a": 1, "b": 2}))
+
+		m, err := MapOf[string, interface{}](New(server{"a", time.Second}))
+		Expect(err).Should(BeNil())
+		Expect(m).Should(Equal(map[string]interface{}{"Name": "a", "Timeout": time.Second}))
+	})
+})
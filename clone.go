@@ -0,0 +1,138 @@
+package value
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// CloneOptions is the options of Value.CloneWithOptions.
+type CloneOptions struct {
+	// Unexported copies the unexported struct fields deeply,
+	// they are copied shallowly by default, that is shared with the original.
+	Unexported bool
+}
+
+// Clone returns a deep copy of t, that mutating it never affects t.
+//
+// The maps, slices, arrays, structs and pointers are copied recursively,
+// the pointers to the same value, and the maps and slices with the same header
+// shared in t are shared in the copy too, so that the cycles are kept.
+// The sub-slices of a backing array and the pointers into a slice, an array
+// or a struct are copied separately, that they don't alias in the copy.
+// The structs implementing encoding.TextUnmarshaler like time.Time,
+// the channels, functions and unsafe pointers are copied as is.
+// The copy keeps the source positions of t.
+func (v *Value) Clone() *Value {
+	return v.CloneWithOptions(CloneOptions{})
+}
+
+// CloneWithOptions same as Clone with options.
+func (v *Value) CloneWithOptions(opts CloneOptions) *Value {
+	return &Value{rv: newCloner(opts).clone(v.getrv()), pos: v.pos, at: v.at}
+}
+
+// cloneKey identifies a reference, the slices are identified with their length too.
+type cloneKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+type cloner struct {
+	opts   CloneOptions
+	copied map[cloneKey]reflect.Value
+}
+
+func newCloner(opts CloneOptions) *cloner {
+	return &cloner{opts: opts, copied: map[cloneKey]reflect.Value{}}
+}
+
+func (c *cloner) clone(rv reflect.Value) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+		cp := reflect.New(rv.Type()).Elem()
+		cp.Set(c.clone(rv.Elem()))
+		return cp
+
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return rv
+		}
+		key := cloneKey{rv.Type(), rv.Pointer(), 0}
+		if cp, ok := c.copied[key]; ok {
+			return cp
+		}
+		cp := reflect.New(rv.Type().Elem())
+		c.copied[key] = cp
+		cp.Elem().Set(c.clone(rv.Elem()))
+		return cp
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+		key := cloneKey{rv.Type(), rv.Pointer(), 0}
+		if cp, ok := c.copied[key]; ok {
+			return cp
+		}
+		cp := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		c.copied[key] = cp
+		iter := rv.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(c.clone(iter.Key()), c.clone(iter.Value()))
+		}
+		return cp
+
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+		key := cloneKey{rv.Type(), rv.Pointer(), rv.Len()}
+		if cp, ok := c.copied[key]; ok {
+			return cp
+		}
+		cp := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Cap())
+		c.copied[key] = cp
+		for i := 0; i < rv.Len(); i++ {
+			cp.Index(i).Set(c.clone(rv.Index(i)))
+		}
+		return cp
+
+	case reflect.Array:
+		cp := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			cp.Index(i).Set(c.clone(rv.Index(i)))
+		}
+		return cp
+
+	case reflect.Struct:
+		cp := reflect.New(rv.Type()).Elem()
+		cp.Set(rv)
+		if reflect.PtrTo(rv.Type()).Implements(textUnmarshalerType) {
+			return cp
+		}
+		for i := 0; i < cp.NumField(); i++ {
+			fv := cp.Field(i)
+			if !fv.CanSet() {
+				if !c.opts.Unexported {
+					continue
+				}
+				fv = reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem()
+			}
+			fv.Set(c.clone(fv))
+		}
+		return cp
+
+	default:
+		if !rv.CanAddr() {
+			return rv
+		}
+		// copy the addressable scalar, that may be changed after cloned, like the field moved by patch
+		cp := reflect.New(rv.Type()).Elem()
+		cp.Set(rv)
+		return cp
+	}
+}
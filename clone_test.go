@@ -0,0 +1,77 @@
+package value
+
+import (
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clone", func() {
+	type node struct {
+		Name string
+		Next *node
+		Tags []string
+		At   time.Time
+		meta map[string]int
+	}
+
+	Specify("no aliasing", func() {
+		x := map[string]interface{}{
+			"a": map[string]interface{}{"b": []interface{}{1, "c"}},
+			"n": &node{Name: "n", Tags: []string{"t"}},
+			"r": [2][]int{{1}, {2}},
+		}
+		v := New(x)
+		c := v.Clone()
+		Expect(c.Interface()).Should(Equal(x))
+
+		Expect(c.PutPath("a.b[0]", 2)).Should(BeNil())
+		Expect(c.PutPath("n.Name", "m")).Should(BeNil())
+		Expect(c.PutPath("n.Tags[0]", "u")).Should(BeNil())
+		c.MustGetPath("r").Interface().([2][]int)[0][0] = 3
+		Expect(x["a"]).Should(Equal(map[string]interface{}{"b": []interface{}{1, "c"}}))
+		Expect(x["n"]).Should(Equal(&node{Name: "n", Tags: []string{"t"}}))
+		Expect(x["r"]).Should(Equal([2][]int{{1}, {2}}))
+
+		Expect(New(nil).Clone().Interface()).Should(BeNil())
+		Expect(New(1).Clone().Interface()).Should(Equal(1))
+	})
+	Specify("shared references and cycles", func() {
+		shared := &node{Name: "s"}
+		tags := []string{"a", "b"}
+		x := []*node{{Name: "a", Next: shared, Tags: tags}, {Name: "b", Next: shared, Tags: tags}}
+		y := New(x).Clone().Interface().([]*node)
+		Expect(y[0].Next).Should(BeIdenticalTo(y[1].Next))
+		Expect(y[0].Next).ShouldNot(BeIdenticalTo(shared))
+		y[0].Tags[0] = "c"
+		Expect(y[1].Tags[0]).Should(Equal("c"))
+		Expect(tags[0]).Should(Equal("a"))
+
+		n := &node{Name: "n"}
+		n.Next = n
+		m := New(n).Clone().Interface().(*node)
+		Expect(m.Next).Should(BeIdenticalTo(m))
+		Expect(m).ShouldNot(BeIdenticalTo(n))
+
+		mm := map[string]interface{}{}
+		mm["self"] = mm
+		cm := New(mm).Clone().Interface().(map[string]interface{})
+		Expect(reflect.ValueOf(cm["self"]).Pointer()).Should(Equal(reflect.ValueOf(cm).Pointer()))
+		Expect(reflect.ValueOf(cm).Pointer()).ShouldNot(Equal(reflect.ValueOf(mm).Pointer()))
+	})
+	Specify("with unexported fields", func() {
+		at := time.Now()
+		x := node{At: at, meta: map[string]int{"a": 1}}
+		y := New(x).Clone().Interface().(node)
+		Expect(y.At).Should(Equal(at))
+		y.meta["a"] = 2
+		Expect(x.meta["a"]).Should(Equal(2))
+
+		y = New(x).CloneWithOptions(CloneOptions{Unexported: true}).Interface().(node)
+		Expect(y.At).Should(Equal(at))
+		y.meta["a"] = 3
+		Expect(x.meta["a"]).Should(Equal(2))
+	})
+})
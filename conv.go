@@ -1,45 +1,31 @@
 package value
 
 import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"math/bits"
 	"net"
 	"net/mail"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/maltegrosse/go-bytesize"
 )
 
-var (
-	// TimeLayout default time layout
-	TimeLayout = "Mon Jan 2 15:04:05 -0700 MST 2006"
-)
-
 type (
 	ByteSize bytesize.ByteSize
 )
 
 var (
-	intLevel = map[reflect.Kind]int{
-		reflect.Int8:  1,
-		reflect.Int16: 2,
-		reflect.Int32: 3,
-		reflect.Int64: 4,
-	}
-	uintLevel = map[reflect.Kind]int{
-		reflect.Uint8:  1,
-		reflect.Uint16: 2,
-		reflect.Uint32: 3,
-		reflect.Uint64: 4,
-	}
-	floatLevel = map[reflect.Kind]int{
-		reflect.Float32: 1,
-		reflect.Float64: 2,
-	}
+	// valueType is the type of *Value, that the source is kept as is when conversion.
+	valueType = reflect.TypeOf((*Value)(nil))
+
 	complexLevel = map[reflect.Kind]int{
 		reflect.Complex64:  1,
 		reflect.Complex128: 2,
@@ -53,29 +39,67 @@ func ConvTo(src, dst interface{}) error {
 
 // ConvTo convToert t to dst
 func (v *Value) ConvTo(dst interface{}) error {
+	return v.ConvToWithOptions(dst, ConvOptions{})
+}
+
+// ConvToWithOptions convert t to dst with options.
+//
+// The conversion continues past failures of map, array, slice and struct elements,
+// and the errors are returned as an ErrMulti of ErrPath, which path is the element's path like "servers[2].timeout",
+// and which has the source position if t is created by NewWithPositions.
+// It returns ErrUnmatched with all the unused keys and unset fields,
+// if the ConvOptions.ErrorUnused or ConvOptions.ErrorUnset is set.
+// The ConvOptions.Metadata is filled with the decoded keys, unused keys and unset fields if it's not nil.
+func (v *Value) ConvToWithOptions(dst interface{}, opts ConvOptions) error {
 	dstv := reflect.ValueOf(dst)
 	if dstv.Kind() != reflect.Ptr {
 		return &ErrUnsupportedKind{"Value.ConvTo", dstv.Kind()}
 	}
-	return v.convTo(dstv.Elem())
+	if dstv.IsNil() {
+		return &ErrCannotBeNil{"Value.ConvTo"}
+	}
+
+	s := newConvState(opts)
+	s.src = v
+	err := v.convTo(s, dstv.Elem())
+	s.fillMetadata()
+	if err != nil {
+		return err
+	}
+	return s.err()
 }
 
-func (v *Value) convTo(dst reflect.Value) (err error) {
-	switch dst.Type().String() {
-	case "time.Duration":
-		return v.convToTimeDuration(dst)
-	case "time.Time":
-		return v.convToTimeTime(dst)
-	case "net.IP":
-		return v.convToNetIP(dst)
-	case "url.URL":
-		return v.convToNetURL(dst)
-	case "mail.Address":
-		return v.convToMailAddress(dst)
-	case "regexp.Regexp":
-		return v.convToRegexpRegexp(dst)
-	case "value.ByteSize":
-		return v.convToByteSize(dst)
+func (v *Value) convTo(s *convState, dst reflect.Value) (err error) {
+	if dst.Type() == valueType {
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	}
+	if !indirect(v.getrv()).IsValid() {
+		switch dst.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+	}
+	if s.opts.WeaklyTyped && v.convToWeakZero(dst) {
+		return nil
+	}
+	if fn := s.conv.lookup(dst.Type()); fn != nil {
+		return fn(v, dst)
+	}
+	if dst.Type() == timeType {
+		return v.convToTimeTime(s.timeFmt, dst)
+	}
+	if ok, err := v.convToUnmarshaler(dst); ok {
+		return err
+	}
+	if ok, err := v.convToParsed(s, dst); ok {
+		return err
+	}
+	if s.opts.WeaklyTyped {
+		if ok, err := v.convToWeak(s, dst); ok {
+			return err
+		}
 	}
 
 	switch dst.Kind() {
@@ -98,51 +122,136 @@ func (v *Value) convTo(dst reflect.Value) (err error) {
 		return v.convToString(dst)
 
 	case reflect.Map:
-		return v.convToMap(dst)
+		return v.convToMap(s, dst)
 
 	case reflect.Array:
-		return v.convToArray(dst)
+		return v.convToArray(s, dst)
 
 	case reflect.Slice:
-		return v.convToSlice(dst)
+		return v.convToSlice(s, dst)
 
 	case reflect.Struct:
-		return v.convToStruct(dst)
+		return v.convToStruct(s, dst)
 
 	case reflect.Interface:
 		return v.convToInterface(dst)
 
 	case reflect.Ptr:
-		return v.convToPtr(dst)
+		return v.convToPtr(s, dst)
 
 	default:
 		return &ErrUnsupportedKind{"Value.convTo", dst.Kind()}
 	}
 }
 
-func (v *Value) convToTimeDuration(dst reflect.Value) error {
-	s, err := v.String()
-	if err != nil {
-		return err
+// convToParsed converts string t to the composite dst by parsing it as formatted by Value.String,
+// the bytes are set as is, or decoded from base64 if t is in the parsed JSON,
+// and the others are parsed as JSON.
+// It returns false if t is not a string or dst is not a composite,
+// or the JSON is invalid in weakly typed conversion.
+func (v *Value) convToParsed(s *convState, dst reflect.Value) (bool, error) {
+	src := indirect(v.getrv())
+	if src.Kind() != reflect.String {
+		return false, nil
 	}
-	td, err := time.ParseDuration(s)
+	switch dst.Kind() {
+	case reflect.Slice, reflect.Array:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			b := []byte(src.String())
+			if s.parsed {
+				var err error
+				if b, err = base64.StdEncoding.DecodeString(src.String()); err != nil {
+					return true, err
+				}
+			}
+			if dst.Kind() == reflect.Slice {
+				dst.SetBytes(b)
+			} else {
+				reflect.Copy(dst, reflect.ValueOf(b))
+			}
+			return true, nil
+		}
+	case reflect.Map, reflect.Struct:
+	default:
+		return false, nil
+	}
+
+	x, err := parseComposite(src.String())
 	if err != nil {
-		return err
+		return !s.opts.WeaklyTyped, err
 	}
-	dst.SetInt(int64(td))
-	return nil
+	defer func(parsed bool) { s.parsed = parsed }(s.parsed)
+	s.parsed = true
+	return true, New(x).convTo(s, dst)
 }
 
-func (v *Value) convToTimeTime(dst reflect.Value) error {
+// convToAt converts t to dst which is at key of current path,
+// the error is collected to s with the path, and it returns false if failed.
+func (v *Value) convToAt(s *convState, key string, index bool, dst reflect.Value) bool {
+	s.push(key, index)
+	defer s.pop()
+
+	if err := v.convTo(s, dst); err != nil {
+		s.fail(err)
+		return false
+	}
+	s.decoded()
+	return true
+}
+
+// convToUnmarshaler converts t to dst with the unmarshaler implemented by dst's pointer.
+// The encoding.BinaryUnmarshaler is used for []byte, the encoding.TextUnmarshaler is used
+// for non-composite value, otherwise the json.Unmarshaler is used with t's JSON encoding.
+// It returns false if dst implements none of them for t.
+func (v *Value) convToUnmarshaler(dst reflect.Value) (bool, error) {
+	if dst.Kind() == reflect.Ptr || dst.Kind() == reflect.Interface || !dst.CanSet() {
+		return false, nil
+	}
+	u := dst.Addr().Interface()
+
+	src := indirect(v.getrv())
+	isBytes := src.Kind() == reflect.Slice && src.Type().Elem().Kind() == reflect.Uint8
+	if isBytes {
+		if bu, ok := u.(encoding.BinaryUnmarshaler); ok {
+			return true, bu.UnmarshalBinary(src.Bytes())
+		}
+		if tu, ok := u.(encoding.TextUnmarshaler); ok {
+			return true, tu.UnmarshalText(src.Bytes())
+		}
+	}
+
+	switch src.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+	default:
+		if tu, ok := u.(encoding.TextUnmarshaler); ok {
+			s, err := v.String()
+			if err != nil {
+				return true, err
+			}
+			return true, tu.UnmarshalText([]byte(s))
+		}
+	}
+
+	if ju, ok := u.(json.Unmarshaler); ok {
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return true, err
+		}
+		return true, ju.UnmarshalJSON(data)
+	}
+	return false, nil
+}
+
+func (v *Value) convToTimeDuration(dst reflect.Value) error {
 	s, err := v.String()
 	if err != nil {
 		return err
 	}
-	t, err := time.Parse(TimeLayout, s)
+	td, err := time.ParseDuration(s)
 	if err != nil {
 		return err
 	}
-	dst.Set(reflect.ValueOf(t))
+	dst.SetInt(int64(td))
 	return nil
 }
 
@@ -221,12 +330,12 @@ func (v *Value) convToByteSize(dst reflect.Value) error {
 	return nil
 }
 
-func (v *Value) convToPtr(dst reflect.Value) error {
+func (v *Value) convToPtr(s *convState, dst reflect.Value) error {
 	realdst := dst
 	if dst.IsNil() {
 		realdst = reflect.New(dst.Type().Elem())
 	}
-	if err := v.convTo(realdst.Elem()); err != nil {
+	if err := v.convTo(s, realdst.Elem()); err != nil {
 		return err
 	}
 	dst.Set(realdst)
@@ -250,29 +359,7 @@ func (v *Value) convToBool(dst reflect.Value) error {
 }
 
 func (v *Value) convToInt(dst reflect.Value) error {
-	vk := v.getrv().Kind()
-	dstk := dst.Kind()
-
-	if dstk == reflect.Int { // convToert Int to Int32 or Int64
-		fixedk := reflect.Invalid // fixed size kind
-		if bits.UintSize == 32 {
-			fixedk = reflect.Int32
-		}
-		if bits.UintSize == 64 {
-			fixedk = reflect.Int64
-		}
-		dstk = fixedk
-	}
-
-	if intLevel[dstk] < intLevel[vk] {
-		return &ErrTypeUnequal{
-			"Value.convToInt",
-			dstk,
-			vk,
-		}
-	}
-
-	iv, err := v.Int64()
+	iv, err := v.toInt("Value.convToInt", dst.Kind())
 	if err != nil {
 		return err
 	}
@@ -281,29 +368,7 @@ func (v *Value) convToInt(dst reflect.Value) error {
 }
 
 func (v *Value) convToUint(dst reflect.Value) error {
-	vk := v.getrv().Kind()
-	dstk := dst.Kind()
-
-	if dstk == reflect.Uint { // convToert Uint to Uint32 or Uint64
-		fixedk := reflect.Invalid
-		if bits.UintSize == 32 {
-			fixedk = reflect.Uint32
-		}
-		if bits.UintSize == 64 {
-			fixedk = reflect.Uint64
-		}
-		dstk = fixedk
-	}
-
-	if uintLevel[dstk] < uintLevel[vk] {
-		return &ErrTypeUnequal{
-			"Value.convToUint",
-			dstk,
-			vk,
-		}
-	}
-
-	uv, err := v.Uint64()
+	uv, err := v.toUint("Value.convToUint", dst.Kind())
 	if err != nil {
 		return err
 	}
@@ -312,18 +377,7 @@ func (v *Value) convToUint(dst reflect.Value) error {
 }
 
 func (v *Value) convToFloat(dst reflect.Value) error {
-	vk := v.getrv().Kind()
-	dstk := dst.Kind()
-
-	if floatLevel[dstk] < floatLevel[vk] {
-		return &ErrTypeUnequal{
-			"Value.convToFloat",
-			dstk,
-			vk,
-		}
-	}
-
-	fv, err := v.Float64()
+	fv, err := v.toFloat("Value.convToFloat", dst.Kind())
 	if err != nil {
 		return err
 	}
@@ -360,7 +414,7 @@ func (v *Value) convToString(dst reflect.Value) error {
 	return nil
 }
 
-func (v *Value) convToMap(dst reflect.Value) error {
+func (v *Value) convToMap(s *convState, dst reflect.Value) error {
 	if dst.IsNil() {
 		dst.Set(reflect.MakeMap(dst.Type()))
 	}
@@ -370,22 +424,26 @@ func (v *Value) convToMap(dst reflect.Value) error {
 		return err
 	}
 	for srck, srcv := range vm {
-		dstk := srck.getrv()
-		dstv := dst.MapIndex(dstk)
-		if dstv.Kind() == reflect.Invalid {
-			dstv = reflect.New(dst.Type().Elem())
-			dstv = dstv.Elem()
+		dstk := reflect.New(dst.Type().Key()).Elem()
+		if err := srck.convTo(s, dstk); err != nil {
+			s.push(srck.keyString(), false)
+			s.fail(err)
+			s.pop()
+			continue
+		}
+		dstv := reflect.New(dst.Type().Elem()).Elem()
+		if old := dst.MapIndex(dstk); old.IsValid() {
+			dstv.Set(old)
 		}
 
-		if err := srcv.convTo(dstv); err != nil {
-			return err
+		if srcv.convToAt(s, srck.keyString(), false, dstv) {
+			dst.SetMapIndex(dstk, dstv)
 		}
-		dst.SetMapIndex(dstk, dstv)
 	}
 	return nil
 }
 
-func (v *Value) convToArray(dst reflect.Value) error {
+func (v *Value) convToArray(s *convState, dst reflect.Value) error {
 	vs, err := v.Slice()
 	if err != nil {
 		return err
@@ -398,14 +456,12 @@ func (v *Value) convToArray(dst reflect.Value) error {
 
 		ev := dst.Index(idx)
 
-		if err := elem.convTo(ev); err != nil {
-			return err
-		}
+		elem.convToAt(s, strconv.Itoa(idx), true, ev)
 	}
 	return nil
 }
 
-func (v *Value) convToSlice(dst reflect.Value) error {
+func (v *Value) convToSlice(s *convState, dst reflect.Value) error {
 	vs, err := v.Slice()
 	if err != nil {
 		return err
@@ -422,70 +478,230 @@ func (v *Value) convToSlice(dst reflect.Value) error {
 			ev = newSlice.Index(i)
 		}
 
-		if err := v.convTo(ev); err != nil {
-			return err
-		}
+		v.convToAt(s, strconv.Itoa(i), true, ev)
 	}
 	dst.Set(newSlice)
 	return nil
 }
 
-func (v *Value) convToStruct(dst reflect.Value) error {
+func (v *Value) convToStruct(s *convState, dst reflect.Value) error {
 	vm, err := v.Map()
 	if err != nil {
 		return err
 	}
 
-	dstype := dst.Type()
-	tag2Fname := map[string]string{}
-	lower2Fname := map[string]string{}
+	// the time format of tag applies to the field itself but not the nested fields
+	defer func(tf timeFormat) { s.timeFmt = tf }(s.timeFmt)
+	s.timeFmt = s.timeBase
+
+	fields := structFields(dst.Type())
+	mapper := s.opts.FieldNameMapper
+	if mapper == nil {
+		mapper = MatchCaseInsensitive
+	}
+	key2Field := map[string]int{}
+	mapped2Field := map[string]int{}
 	passedFnames := map[string]bool{}
-	for i := 0; i < dstype.NumField(); i++ {
-		field := dstype.Field(i)
-		tag := field.Tag.Get("value")
-		if tag == "-" {
-			passedFnames[field.Name] = true
-		} else if tag != "" {
-			tag2Fname[tag] = field.Name
-		} else {
-			lower2Fname[strings.ToLower(field.Name)] = field.Name
+	remain := -1
+	for i, f := range fields {
+		if f.passed {
+			passedFnames[f.Name] = true
+			continue
+		}
+		if f.remain {
+			if remain < 0 {
+				remain = i
+			}
+			continue
+		}
+		key2Field[f.key] = i
+		if f.tagged {
+			continue
+		}
+		if _, ok := mapped2Field[mapper(f.Name)]; !ok {
+			mapped2Field[mapper(f.Name)] = i
 		}
 	}
 
-	matchCase := false
-	for kv := range vm {
+	keys := make([]string, 0, len(vm))
+	vals := make(map[string]*Value, len(vm))
+	for kv, vv := range vm {
 		key, err := kv.String()
 		if err != nil {
 			return err
 		}
-		if strings.ToLower(key) != key {
-			matchCase = true
+		if passedFnames[key] {
+			continue
+		}
+		keys = append(keys, key)
+		vals[key] = vv
+	}
+	sort.Strings(keys)
+
+	// the keys match exactly take precedence over the mapped ones,
+	// and the other keys matching the same field are unused.
+	matched := make([]bool, len(fields))
+	key2Match := map[string]int{}
+	for _, key := range keys {
+		if i, ok := key2Field[key]; ok {
+			key2Match[key] = i
+			matched[i] = true
 		}
 	}
-	for kv, vv := range vm {
-		key, err := kv.String()
+	remains := map[string]*Value{}
+	for _, key := range keys {
+		if _, ok := key2Match[key]; ok {
+			continue
+		}
+		if i, ok := mapped2Field[mapper(key)]; ok && !matched[i] {
+			key2Match[key] = i
+			matched[i] = true
+		} else if remain >= 0 {
+			remains[key] = vals[key]
+		} else {
+			s.addUnused(key)
+		}
+	}
+
+	for _, key := range keys {
+		i, ok := key2Match[key]
+		if !ok {
+			continue
+		}
+		f := fields[i]
+		tf := s.timeFmt
+		ftf, err := tf.field(f.StructField)
 		if err != nil {
-			return err
+			s.push(key, false)
+			s.fail(err)
+			s.pop()
+			continue
 		}
-		if passedFnames[key] {
+		s.timeFmt = ftf
+		fv := fieldByIndex(dst, f.index, true)
+		if vals[key].convToAt(s, key, false, fv) {
+			s.validateAt(key, f.StructField, fv)
+		}
+		s.timeFmt = tf
+	}
+
+	if remain >= 0 && len(remains) > 0 {
+		f := fields[remain]
+		s.convToRemain(f.key, remains, fieldByIndex(dst, f.index, true))
+	}
+
+	for i, f := range fields {
+		if f.passed || f.remain || matched[i] {
 			continue
 		}
-		fn := tag2Fname[key]
-		if fn == "" {
-			if matchCase {
-				fn = key
-			} else {
-				fn = lower2Fname[key]
+		s.push(f.key, false)
+		if _, opts := parseTag(fieldTag(f.StructField)); opts.Has("required") {
+			s.fail(&ErrNotExist{"Value.ConvTo", f.key + " key"})
+			s.pop()
+			continue
+		}
+		_, hasDefault := fieldDefault(f.StructField)
+		fv := fieldByIndex(dst, f.index, hasDefault)
+		defaulted := fv.IsValid() && s.convToDefault(f.StructField, fv)
+		s.pop()
+		if !defaulted {
+			s.addUnset(f.key)
+		}
+	}
+	return nil
+}
+
+// convToRemain puts the unmatched key value pairs to the remain field dst,
+// which must be a map with string keys, like map[string]interface{} or map[string]*Value.
+// The errors are reported at the keys of source rather than the field.
+func (s *convState) convToRemain(fkey string, remains map[string]*Value, dst reflect.Value) {
+	t := dst.Type()
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		s.push(fkey, false)
+		s.fail(&ErrUnsupportedKind{"Value.ConvTo", "remain field of " + t.String()})
+		s.pop()
+		return
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMap(t))
+	}
+	for key, vv := range remains {
+		ev := reflect.New(t.Elem()).Elem()
+		if vv.convToAt(s, key, false, ev) {
+			dst.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), ev)
+		}
+	}
+}
+
+// convToDefault sets dst to the default value of field, or sets the default values
+// of the fields recursively if dst is a struct or a non-nil pointer to struct.
+// It returns false if there is no default value.
+//
+// The default value is converted as the source value,
+// and it's split by comma if dst is an array or slice.
+func (s *convState) convToDefault(field reflect.StructField, dst reflect.Value) bool {
+	if def, ok := fieldDefault(field); ok {
+		var src interface{} = def
+		t := dst.Type()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && s.conv.lookup(t) == nil {
+			elems := []string{}
+			if def != "" {
+				elems = strings.Split(def, ",")
+			}
+			for i := range elems {
+				elems[i] = strings.TrimSpace(elems[i])
 			}
+			src = elems
+		}
+
+		tf := s.timeFmt
+		ftf, err := tf.field(field)
+		if err == nil {
+			s.timeFmt, s.defaulting = ftf, true
+			err = New(src).convTo(s, dst)
+			s.timeFmt, s.defaulting = tf, false
+		}
+		if err == nil {
+			s.addDefaulted()
+			err = s.validate(field, dst)
+		}
+		if err != nil {
+			s.fail(err)
 		}
-		f := dst.FieldByName(fn)
-		if f.Kind() == reflect.Invalid {
+		return true
+	}
+
+	rv := dst
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || s.conv.lookup(rv.Type()) != nil {
+		return false
+	}
+
+	defer func(tf timeFormat) { s.timeFmt = tf }(s.timeFmt)
+	s.timeFmt = s.timeBase
+	defaulted := false
+	for _, f := range structFields(rv.Type()) {
+		if f.passed {
 			continue
 		}
-		if err := vv.convTo(f); err != nil {
-			return err
+		_, hasDefault := fieldDefault(f.StructField)
+		fv := fieldByIndex(rv, f.index, hasDefault)
+		if !fv.IsValid() {
+			continue
 		}
-		passedFnames[fn] = true
+		s.push(f.key, false)
+		if s.convToDefault(f.StructField, fv) {
+			defaulted = true
+		}
+		s.pop()
 	}
-	return nil
+	return defaulted
 }
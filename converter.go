@@ -0,0 +1,82 @@
+package value
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ConvertFunc converts src to dst, the dst is settable.
+type ConvertFunc func(src *Value, dst reflect.Value) error
+
+// Converter is a registry of ConvertFunc keyed by the destination type,
+// which is consulted by ConvTo before the kind-based conversion.
+//
+// The registrations of a Converter created by NewConverter are scoped to itself,
+// and it falls back to the global registrations of RegisterConverter.
+type Converter struct {
+	parent *Converter
+
+	mu    sync.RWMutex
+	funcs map[reflect.Type]ConvertFunc
+}
+
+var defaultConverter = &Converter{
+	funcs: map[reflect.Type]ConvertFunc{
+		reflect.TypeOf(time.Duration(0)): (*Value).convToTimeDuration,
+		reflect.TypeOf(net.IP{}):         (*Value).convToNetIP,
+		reflect.TypeOf(url.URL{}):        (*Value).convToNetURL,
+		reflect.TypeOf(mail.Address{}):   (*Value).convToMailAddress,
+		reflect.TypeOf(regexp.Regexp{}):  (*Value).convToRegexpRegexp,
+		reflect.TypeOf(ByteSize(0)):      (*Value).convToByteSize,
+	},
+}
+
+// NewConverter new a Converter falls back to the global registrations.
+func NewConverter() *Converter {
+	return &Converter{
+		parent: defaultConverter,
+		funcs:  map[reflect.Type]ConvertFunc{},
+	}
+}
+
+// RegisterConverter registers fn globally for converting to typ,
+// it replaces the previous one, and a nil fn removes it.
+func RegisterConverter(typ reflect.Type, fn ConvertFunc) {
+	defaultConverter.Register(typ, fn)
+}
+
+// Register registers fn to c for converting to typ,
+// it replaces the previous one, and a nil fn removes it.
+func (c *Converter) Register(typ reflect.Type, fn ConvertFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if fn == nil {
+		delete(c.funcs, typ)
+		return
+	}
+	c.funcs[typ] = fn
+}
+
+// ConvTo converts src to dst with c, the dst must be a pointer.
+func (c *Converter) ConvTo(src, dst interface{}) error {
+	return New(src).ConvToWithOptions(dst, ConvOptions{Converter: c})
+}
+
+// lookup returns the ConvertFunc for typ, or nil if not registered.
+func (c *Converter) lookup(typ reflect.Type) ConvertFunc {
+	for ; c != nil; c = c.parent {
+		c.mu.RLock()
+		fn := c.funcs[typ]
+		c.mu.RUnlock()
+		if fn != nil {
+			return fn
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package value
+
+import (
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type convTestID struct {
+	prefix string
+	n      int
+}
+
+type convTestDuration int64
+
+func convToConvTestID(src *Value, dst reflect.Value) error {
+	s, err := src.String()
+	if err != nil {
+		return err
+	}
+	i := strings.IndexByte(s, '-')
+	if i < 0 {
+		return &ErrNotExist{"convToConvTestID", "separator"}
+	}
+	n, err := New(s[i+1:]).Int()
+	if err != nil {
+		return err
+	}
+	dst.Set(reflect.ValueOf(convTestID{s[:i], n}))
+	return nil
+}
+
+var _ = Describe("Converter", func() {
+	idType := reflect.TypeOf(convTestID{})
+
+	Specify("global registration", func() {
+		RegisterConverter(idType, convToConvTestID)
+		defer RegisterConverter(idType, nil)
+
+		var y struct {
+			ID  convTestID
+			IDs []*convTestID
+		}
+		x := map[string]interface{}{
+			"id":  "a-1",
+			"ids": []string{"b-2"},
+		}
+		Expect(ConvTo(x, &y)).Should(BeNil())
+		Expect(y.ID).Should(Equal(convTestID{"a", 1}))
+		Expect(y.IDs).Should(Equal([]*convTestID{{"b", 2}}))
+	})
+	Specify("scoped registration", func() {
+		c := NewConverter()
+		c.Register(idType, convToConvTestID)
+
+		var y convTestID
+		Expect(c.ConvTo("a-1", &y)).Should(BeNil())
+		Expect(y).Should(Equal(convTestID{"a", 1}))
+
+		Expect(ConvTo("a-1", &y)).ShouldNot(BeNil())
+	})
+	Specify("falls back to global registration", func() {
+		c := NewConverter()
+		var y ByteSize
+		Expect(c.ConvTo("1KB", &y)).Should(BeNil())
+		Expect(y).Should(Equal(ByteSize(1024)))
+	})
+	Specify("keyed by type rather than name", func() {
+		var y convTestDuration
+		Expect(ConvTo("1s", &y)).ShouldNot(BeNil())
+		Expect(ConvTo(2, &y)).Should(BeNil())
+		Expect(y).Should(Equal(convTestDuration(2)))
+	})
+	Specify("nil ptr", func() {
+		var y *int
+		Expect(ConvTo(1, y)).To(BeAssignableToTypeOf((*ErrCannotBeNil)(nil)))
+	})
+})
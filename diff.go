@@ -0,0 +1,351 @@
+package value
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CompareOptions is the options of Value.EqualWithOptions and DiffWithOptions.
+type CompareOptions struct {
+	// IgnoreNumericKind compares the numbers by value regardless of their kinds,
+	// such as int8(1) equals float64(1) and json.Number("1").
+	IgnoreNumericKind bool
+}
+
+// ChangeType is the type of Change.
+type ChangeType int
+
+const (
+	// ChangeAdded is a value only in the second one.
+	ChangeAdded ChangeType = iota + 1
+
+	// ChangeRemoved is a value only in the first one.
+	ChangeRemoved
+
+	// ChangeModified is a value different in the two.
+	ChangeModified
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return "ChangeType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// Change is a difference of two values at path.
+// From is nil if it's added, and To is nil if it's removed.
+// The key of path is quoted like `a["b.c"]` if it's empty or contains '.', '[', ']' or '"'.
+type Change struct {
+	Type ChangeType
+	Path string
+	From interface{}
+	To   interface{}
+}
+
+// String returns the change like `modified a.b: 1 -> 2`.
+func (c Change) String() string {
+	s := c.Type.String() + " " + strconv.Quote(c.Path) + ": "
+	switch c.Type {
+	case ChangeAdded:
+		return s + changeString(c.To)
+	case ChangeRemoved:
+		return s + changeString(c.From)
+	default:
+		return s + changeString(c.From) + " -> " + changeString(c.To)
+	}
+}
+
+// Pointer returns the path as JSON Pointer (RFC 6901) like "/servers/2/port".
+func (c Change) Pointer() string {
+	var segs []pathSeg
+	for p := c.Path; p != ""; {
+		var key string
+		switch {
+		case p[0] == '.':
+			p = p[1:]
+			continue
+		case strings.HasPrefix(p, `["`):
+			j := 2
+			for j < len(p) && p[j] != '"' {
+				if p[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			key, _ = strconv.Unquote(p[1 : j+1])
+			p = p[j+2:]
+		case p[0] == '[':
+			j := strings.IndexByte(p, ']')
+			key, p = p[1:j], p[j+1:]
+		default:
+			j := strings.IndexAny(p, ".[")
+			if j < 0 {
+				j = len(p)
+			}
+			key, p = p[:j], p[j:]
+		}
+		segs = append(segs, pathSeg{key: key})
+	}
+	return formatPointer(segs)
+}
+
+// changePath formats segs like formatPath, but the key is quoted like `["a.b"]`
+// if it's empty or contains '.', '[', ']' or '"', so that Pointer can split it back.
+func changePath(segs []pathSeg) string {
+	var b strings.Builder
+	for i, seg := range segs {
+		if seg.index {
+			b.WriteString(seg.String())
+		} else {
+			b.WriteString(changeKey(seg.key, i == 0))
+		}
+	}
+	return b.String()
+}
+
+// changeKey formats the key of changePath, that's led by '.' unless it's first or quoted.
+func changeKey(key string, first bool) string {
+	switch {
+	case key == "" || strings.ContainsAny(key, `.[]"`):
+		return "[" + strconv.Quote(key) + "]"
+	case first:
+		return key
+	}
+	return "." + key
+}
+
+func changeString(x interface{}) string {
+	if s, ok := x.(string); ok {
+		return strconv.Quote(s)
+	}
+	s, err := New(x).String()
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	return s
+}
+
+// Equal reports whether t and other are deeply equal, see Diff.
+func (v *Value) Equal(other *Value) bool {
+	return v.EqualWithOptions(other, CompareOptions{})
+}
+
+// EqualWithOptions same as Equal with options.
+func (v *Value) EqualWithOptions(other *Value, opts CompareOptions) bool {
+	d := &differ{opts: opts, first: true, visiting: map[[2]visit]bool{}}
+	d.diff(v.getrv(), other.getrv())
+	return len(d.changes) == 0
+}
+
+// Diff returns the changes from a to b, keyed by paths like "servers[2].port".
+//
+// The pointers and interfaces are followed, the maps and structs are compared by keys,
+// and a struct field is keyed with its tag name or field name, so that a struct equals
+// the map of its fields. The slices and arrays are compared by index.
+// The time.Time are compared with time.Time.Equal, and the other values with reflect.DeepEqual.
+// The changes are ordered by path, an added or removed key is not compared deeper,
+// and the nil map and slice are equal to the empty ones.
+func Diff(a, b *Value) []Change {
+	return DiffWithOptions(a, b, CompareOptions{})
+}
+
+// DiffWithOptions same as Diff with options.
+func DiffWithOptions(a, b *Value, opts CompareOptions) []Change {
+	d := &differ{opts: opts, visiting: map[[2]visit]bool{}}
+	d.diff(a.getrv(), b.getrv())
+	return d.changes
+}
+
+type differ struct {
+	opts     CompareOptions
+	first    bool // stop at the first change
+	path     []pathSeg
+	changes  []Change
+	visiting map[[2]visit]bool
+}
+
+func (d *differ) add(typ ChangeType, a, b reflect.Value) {
+	c := Change{Type: typ, Path: changePath(d.path)}
+	if a.IsValid() && a.CanInterface() {
+		c.From = a.Interface()
+	}
+	if b.IsValid() && b.CanInterface() {
+		c.To = b.Interface()
+	}
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) done() bool {
+	return d.first && len(d.changes) > 0
+}
+
+func (d *differ) diff(a, b reflect.Value) {
+	a, b = derefNonNil(a), derefNonNil(b)
+	if nilA, nilB := isNilRef(a), isNilRef(b); nilA || nilB {
+		if nilA != nilB {
+			d.add(ChangeModified, a, b)
+		}
+		return
+	}
+
+	// the pointers or maps visiting are assumed to be equal
+	if pa, pb := visitOf(a), visitOf(b); pa.ptr != 0 && pb.ptr != 0 {
+		key := [2]visit{pa, pb}
+		if d.visiting[key] {
+			return
+		}
+		d.visiting[key] = true
+		defer delete(d.visiting, key)
+	}
+
+	ka, kb := keyedKind(a), keyedKind(b)
+	switch {
+	case ka == reflect.Map && kb == reflect.Map:
+		d.diffEntries(a, b)
+	case ka == reflect.Slice && kb == reflect.Slice:
+		d.diffElems(a, b)
+	case !d.equalScalar(a, b):
+		d.add(ChangeModified, a, b)
+	}
+}
+
+func (d *differ) diffEntries(a, b reflect.Value) {
+	ea, erra := entriesOf(a)
+	eb, errb := entriesOf(b)
+	if erra != nil || errb != nil {
+		d.add(ChangeModified, a, b)
+		return
+	}
+
+	for i, j := 0, 0; (i < len(ea) || j < len(eb)) && !d.done(); {
+		switch {
+		case j == len(eb) || i < len(ea) && ea[i].name < eb[j].name:
+			d.path = append(d.path, pathSeg{key: ea[i].name})
+			d.add(ChangeRemoved, ea[i].val, reflect.Value{})
+			i++
+		case i == len(ea) || ea[i].name > eb[j].name:
+			d.path = append(d.path, pathSeg{key: eb[j].name})
+			d.add(ChangeAdded, reflect.Value{}, eb[j].val)
+			j++
+		default:
+			d.path = append(d.path, pathSeg{key: ea[i].name})
+			d.diff(ea[i].val, eb[j].val)
+			i, j = i+1, j+1
+		}
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+func (d *differ) diffElems(a, b reflect.Value) {
+	for i := 0; (i < a.Len() || i < b.Len()) && !d.done(); i++ {
+		d.path = append(d.path, pathSeg{key: strconv.Itoa(i), index: true})
+		switch {
+		case i >= b.Len():
+			d.add(ChangeRemoved, a.Index(i), reflect.Value{})
+		case i >= a.Len():
+			d.add(ChangeAdded, reflect.Value{}, b.Index(i))
+		default:
+			d.diff(a.Index(i), b.Index(i))
+		}
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+// equalScalar reports whether the non-keyed values a and b are equal.
+func (d *differ) equalScalar(a, b reflect.Value) bool {
+	if d.opts.IgnoreNumericKind {
+		na, oka := numberOf(a)
+		nb, okb := numberOf(b)
+		if oka || okb {
+			return oka && okb && na != nil && nb != nil && na.Cmp(nb) == 0
+		}
+	}
+
+	if a.Type() != b.Type() {
+		return false
+	}
+	if !a.CanInterface() || !b.CanInterface() {
+		return false
+	}
+	if ta, ok := a.Interface().(time.Time); ok {
+		return ta.Equal(b.Interface().(time.Time))
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// keyedKind returns reflect.Map for the maps and structs compared by keys,
+// reflect.Slice for the slices and arrays compared by index, or the kind of rv.
+func keyedKind(rv reflect.Value) reflect.Kind {
+	if !isComposite(rv) {
+		return rv.Kind()
+	}
+	switch rv.Kind() {
+	case reflect.Map, reflect.Struct:
+		return reflect.Map
+	default:
+		return reflect.Slice
+	}
+}
+
+// derefNonNil follows the non-nil pointers and interfaces of rv,
+// so that rv is a nil pointer or interface if it's still one.
+func derefNonNil(rv reflect.Value) reflect.Value {
+	for (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// isNilRef reports whether rv is invalid, or a nil pointer or interface.
+func isNilRef(rv reflect.Value) bool {
+	return !rv.IsValid() || rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface
+}
+
+// visitOf returns the visit of map rv, or the addressable rv which is pointed.
+func visitOf(rv reflect.Value) visit {
+	switch {
+	case rv.Kind() == reflect.Map:
+		return visit{rv.Type(), rv.Pointer()}
+	case rv.CanAddr():
+		return visit{rv.Type(), rv.UnsafeAddr()}
+	}
+	return visit{}
+}
+
+// numberOf returns the value of number rv, that is an integer, a float or json.Number.
+// The number is nil if it's NaN or an invalid json.Number.
+func numberOf(rv reflect.Value) (*big.Float, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(rv.Float()) {
+			return nil, true
+		}
+		return new(big.Float).SetFloat64(rv.Float()), true
+	case reflect.String:
+		if rv.Type() != reflect.TypeOf(json.Number("")) {
+			return nil, false
+		}
+		f, _, err := big.ParseFloat(rv.String(), 10, 256, big.ToNearestEven)
+		if err != nil {
+			return nil, true
+		}
+		return f, true
+	}
+	return nil, false
+}
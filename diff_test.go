@@ -0,0 +1,92 @@
+package value
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	type server struct {
+		Name string `value:"name"`
+		Port int    `value:"port"`
+	}
+
+	Specify("Equal()", func() {
+		Expect(New(int8(1)).Equal(New(float64(1)))).Should(BeFalse())
+		opts := CompareOptions{IgnoreNumericKind: true}
+		Expect(New(int8(1)).EqualWithOptions(New(float64(1)), opts)).Should(BeTrue())
+		Expect(New(uint64(math.MaxUint64)).EqualWithOptions(New(json.Number("18446744073709551615")), opts)).Should(BeTrue())
+		Expect(New(int64(math.MaxInt64)).EqualWithOptions(New(float64(math.MaxInt64)), opts)).Should(BeFalse())
+		Expect(New(math.NaN()).EqualWithOptions(New(math.NaN()), opts)).Should(BeFalse())
+		Expect(New(1).EqualWithOptions(New("1"), opts)).Should(BeFalse())
+
+		x := map[string]interface{}{"a": []interface{}{1, "b"}, "m": map[string]int(nil)}
+		y := map[string]interface{}{"a": []int{1}, "m": map[string]int{}}
+		Expect(New(x).Equal(New(y))).Should(BeFalse())
+		y["a"] = []interface{}{1, "b"}
+		Expect(New(x).Equal(New(&y))).Should(BeTrue())
+
+		Expect(New(server{"a", 1}).Equal(New(map[string]interface{}{"name": "a", "port": 1}))).Should(BeTrue())
+		t := time.Now()
+		Expect(New(t).Equal(New(t.UTC()))).Should(BeTrue())
+		Expect(New(nil).Equal(New((*server)(nil)))).Should(BeTrue())
+		Expect(New(nil).Equal(New(&server{}))).Should(BeFalse())
+
+		m := map[string]interface{}{}
+		m["m"] = m
+		n := map[string]interface{}{}
+		n["m"] = n
+		Expect(New(m).Equal(New(n))).Should(BeTrue())
+	})
+	Specify("Diff()", func() {
+		a := map[string]interface{}{
+			"host":    "a",
+			"port":    80,
+			"servers": []server{{"a", 1}, {"b", 2}},
+			"tls":     &struct{ Cert string }{"c"},
+			"old":     true,
+		}
+		b := map[string]interface{}{
+			"host":    "a",
+			"port":    int64(81),
+			"servers": []server{{"a", 1}, {"c", 2}, {"d", 3}},
+			"tls":     &struct{ Cert string }{"d"},
+			"new":     nil,
+		}
+		changes := Diff(New(a), New(b))
+		Expect(changes).Should(Equal([]Change{
+			{ChangeAdded, "new", nil, nil},
+			{ChangeRemoved, "old", true, nil},
+			{ChangeModified, "port", 80, int64(81)},
+			{ChangeModified, "servers[1].name", "b", "c"},
+			{ChangeAdded, "servers[2]", nil, server{"d", 3}},
+			{ChangeModified, "tls.Cert", "c", "d"},
+		}))
+		Expect(changes[2].String()).Should(Equal(`modified "port": 80 -> 81`))
+		Expect(changes[3].String()).Should(Equal(`modified "servers[1].name": "b" -> "c"`))
+		Expect(changes[4].String()).Should(Equal(`added "servers[2]": {"name":"d","port":3}`))
+		Expect(changes[1].String()).Should(Equal(`removed "old": true`))
+		Expect(changes[3].Pointer()).Should(Equal("/servers/1/name"))
+
+		b["port"] = 80.0
+		Expect(Diff(New(a), New(b))).Should(HaveLen(6))
+		Expect(DiffWithOptions(New(a), New(b), CompareOptions{IgnoreNumericKind: true})).Should(HaveLen(5))
+		Expect(Diff(New(a), New(a))).Should(BeEmpty())
+	})
+	Specify("Diff() with special keys", func() {
+		a := map[string]interface{}{"a.b": map[string]interface{}{"": []int{1}, "x/y~": 1, `q"[]`: 1}}
+		b := map[string]interface{}{"a.b": map[string]interface{}{"": []int{2}, "x/y~": 2, `q"[]`: 2}}
+		changes := Diff(New(a), New(b))
+		var paths, pointers []string
+		for _, c := range changes {
+			paths = append(paths, c.Path)
+			pointers = append(pointers, c.Pointer())
+		}
+		Expect(paths).Should(Equal([]string{`["a.b"][""][0]`, `["a.b"]["q\"[]"]`, `["a.b"].x/y~`}))
+		Expect(pointers).Should(Equal([]string{"/a.b//0", `/a.b/q"[]`, "/a.b/x~1y~0"}))
+	})
+})
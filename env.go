@@ -0,0 +1,83 @@
+package value
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FromEnv new a Value from the environment variables with prefix,
+// the names are trimmed the prefix, lowercased and split by sep into nested keys,
+// such as APP_DB__HOST=x is {"db": {"host": "x"}} with prefix "APP" and sep "__".
+//
+// The prefix is followed by an underscore if it does not end with one,
+// and all the variables are used if the prefix is empty. The names are not nested if sep is empty.
+// The values are strings, they can be converted with ConvOptions.WeaklyTyped.
+// It returns ErrPath if a name has an empty key like APP_DB____HOST,
+// or is both a value and a parent of others, like APP_DB and APP_DB__HOST.
+func FromEnv(prefix, sep string) (*Value, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	env := os.Environ()
+	sort.Strings(env)
+	root := reflect.ValueOf(map[string]interface{}{})
+	for _, kv := range env {
+		i := strings.IndexByte(kv, '=')
+		if i < 0 || !strings.HasPrefix(kv[:i], prefix) || i == len(prefix) {
+			continue
+		}
+		name, val := kv[:i], kv[i+1:]
+
+		keys := []string{strings.ToLower(name[len(prefix):])}
+		if sep != "" {
+			keys = strings.Split(keys[0], sep)
+		}
+		var segs []pathSeg
+		for _, key := range keys {
+			segs = append(segs, pathSeg{key: key})
+		}
+		if err := putTree(root, segs, val); err != nil {
+			if e, ok := err.(*ErrPath); ok {
+				e.Method, e.Path = "value.FromEnv", name
+			}
+			return nil, err
+		}
+	}
+	return New(root.Interface()), nil
+}
+
+// putTree puts val to the tree of map[string]interface{} root with segs.
+// It fails if a key is empty, or val conflicts with the value put before,
+// that is an existing value at segs or under a parent of segs,
+// so that the result doesn't depend on the order of puts.
+func putTree(root reflect.Value, segs []pathSeg, val interface{}) error {
+	cur := root
+	for i, seg := range segs {
+		if seg.key == "" {
+			return &ErrPath{"value.putTree", "", seg.key, &ErrInvalid{"value.putTree", "non-empty key", seg.key}, nil}
+		}
+		if !cur.IsValid() {
+			continue
+		}
+		child := cur.MapIndex(reflect.ValueOf(seg.key))
+		if !child.IsValid() {
+			cur = child
+			continue
+		}
+		child = child.Elem()
+		switch last := i == len(segs)-1; {
+		case last && child.Kind() == reflect.Map:
+			return &ErrPath{"value.putTree", "", seg.key, &ErrTypeUnequal{"value.putTree", reflect.Map, reflect.ValueOf(val).Kind()}, nil}
+		case last:
+			return &ErrPath{"value.putTree", "", seg.key, &ErrInvalid{"value.putTree", "unique name", seg.key}, nil}
+		case child.Kind() != reflect.Map:
+			return &ErrPath{"value.putTree", "", seg.key, &ErrTypeUnequal{"value.putTree", child.Kind(), reflect.Map}, nil}
+		}
+		cur = child
+	}
+	_, err := putSegs(root, segs, reflect.ValueOf(val))
+	return err
+}
@@ -0,0 +1,93 @@
+package value
+
+import (
+	"os"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FromEnv", func() {
+	setenv := func(kvs map[string]string) {
+		for k, v := range kvs {
+			Expect(os.Setenv(k, v)).Should(Succeed())
+		}
+	}
+	unsetenv := func(kvs map[string]string) {
+		for k := range kvs {
+			os.Unsetenv(k)
+		}
+	}
+
+	Specify("with prefix and separator", func() {
+		env := map[string]string{
+			"GOVALUETEST_DB__HOST":  "x",
+			"GOVALUETEST_DB__PORT":  "5432",
+			"GOVALUETEST_MAX_CONNS": "10",
+			"GOVALUETEST_DEBUG":     "yes",
+			"GOVALUETESTX_IGNORED":  "1",
+			"GOVALUETEST_":          "1",
+		}
+		setenv(env)
+		defer unsetenv(env)
+
+		v, err := FromEnv("GOVALUETEST", "__")
+		Expect(err).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{
+			"db":        map[string]interface{}{"host": "x", "port": "5432"},
+			"max_conns": "10",
+			"debug":     "yes",
+		}))
+
+		var y struct {
+			DB struct {
+				Host string
+				Port int
+			}
+			MaxConns int
+			Debug    bool
+		}
+		opts := ConvOptions{WeaklyTyped: true, FieldNameMapper: MatchSnakeCase}
+		Expect(v.ConvToWithOptions(&y, opts)).Should(BeNil())
+		Expect(y.DB.Host).Should(Equal("x"))
+		Expect(y.DB.Port).Should(Equal(5432))
+		Expect(y.MaxConns).Should(Equal(10))
+		Expect(y.Debug).Should(BeTrue())
+	})
+	Specify("with conflicting names", func() {
+		env := map[string]string{"GOVALUETEST_DB": "x", "GOVALUETEST_DB__HOST": "y"}
+		setenv(env)
+		defer unsetenv(env)
+
+		_, err := FromEnv("GOVALUETEST_", "__")
+		Expect(err).Should(BeAssignableToTypeOf((*ErrPath)(nil)))
+		Expect(err.(*ErrPath).Path).Should(Equal("GOVALUETEST_DB__HOST"))
+	})
+	Specify("with conflicting names in any order", func() {
+		env := map[string]string{"GOVALUETEST_DB": "x", "GOVALUETEST_DB-HOST": "y"}
+		setenv(env)
+		defer unsetenv(env)
+
+		_, err := FromEnv("GOVALUETEST_", "-")
+		Expect(err).Should(Equal(&ErrPath{"value.FromEnv", "GOVALUETEST_DB", "db", &ErrTypeUnequal{"value.putTree", reflect.Map, reflect.String}, nil}))
+	})
+	Specify("with empty keys", func() {
+		env := map[string]string{"GOVALUETEST_DB____HOST": "x"}
+		setenv(env)
+		defer unsetenv(env)
+
+		_, err := FromEnv("GOVALUETEST", "__")
+		Expect(err).Should(BeAssignableToTypeOf((*ErrPath)(nil)))
+		Expect(err.(*ErrPath).Path).Should(Equal("GOVALUETEST_DB____HOST"))
+	})
+	Specify("without separator", func() {
+		env := map[string]string{"GOVALUETEST_DB_HOST": "x"}
+		setenv(env)
+		defer unsetenv(env)
+
+		v, err := FromEnv("GOVALUETEST", "")
+		Expect(err).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{"db_host": "x"}))
+	})
+})
@@ -1,7 +1,10 @@
 package value
 
 import (
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 type (
@@ -9,6 +12,14 @@ type (
 	ErrNumOverflow struct {
 		Method string
 		Kind   reflect.Kind
+		Value  interface{} // source value, nil if unknown
+	}
+
+	// ErrTruncated ...
+	ErrTruncated struct {
+		Method string
+		Kind   reflect.Kind
+		Value  interface{}
 	}
 
 	// ErrUnsupportedKind ...
@@ -44,6 +55,40 @@ type (
 	ErrOutOfRange struct {
 		Method string
 	}
+
+	// ErrCyclic ...
+	ErrCyclic struct {
+		Method string
+	}
+
+	// ErrUnmatched ...
+	ErrUnmatched struct {
+		Method string
+		Unused []string
+		Unset  []string
+	}
+
+	// ErrMulti ...
+	ErrMulti struct {
+		Method string
+		Errs   []error
+	}
+
+	// ErrInvalid ...
+	ErrInvalid struct {
+		Method string
+		Rule   string
+		Value  interface{}
+	}
+
+	// ErrPath ...
+	ErrPath struct {
+		Method  string
+		Path    string
+		Segment string
+		Err     error
+		Pos     *Position // source position of path if known
+	}
 )
 
 func (e *ErrUnsupportedKind) Error() string {
@@ -63,7 +108,14 @@ func (e *ErrUnsupportedKind) Error() string {
 }
 
 func (e *ErrNumOverflow) Error() string {
-	return "table: call of " + e.Method + " overflows " + e.Kind.String()
+	if e.Value == nil {
+		return "table: call of " + e.Method + " overflows " + e.Kind.String()
+	}
+	return "table: call of " + e.Method + " overflows " + e.Kind.String() + " with " + fmt.Sprint(e.Value)
+}
+
+func (e *ErrTruncated) Error() string {
+	return "table: call of " + e.Method + " truncates " + fmt.Sprint(e.Value) + " to " + e.Kind.String()
 }
 
 func (e *ErrCannotBeNil) Error() string {
@@ -85,3 +137,51 @@ func (e *ErrTypeUnequal) Error() string {
 func (e *ErrOutOfRange) Error() string {
 	return "table: call of " + e.Method + " out of range"
 }
+
+func (e *ErrCyclic) Error() string {
+	return "table: call of " + e.Method + " on cyclic value"
+}
+
+func (e *ErrUnmatched) Error() string {
+	var msgs []string
+	if len(e.Unused) > 0 {
+		msgs = append(msgs, "unused keys "+strings.Join(e.Unused, ", "))
+	}
+	if len(e.Unset) > 0 {
+		msgs = append(msgs, "unset fields "+strings.Join(e.Unset, ", "))
+	}
+	return "table: call of " + e.Method + " with " + strings.Join(msgs, " and ")
+}
+
+func (e *ErrMulti) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return "table: call of " + e.Method + " with " + strconv.Itoa(len(e.Errs)) + " errors: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors, which are followed by errors.Is and errors.As since Go 1.20.
+func (e *ErrMulti) Unwrap() []error {
+	return e.Errs
+}
+
+func (e *ErrInvalid) Error() string {
+	return "table: call of " + e.Method + " violates " + e.Rule + " with " + fmt.Sprint(e.Value)
+}
+
+func (e *ErrPath) Error() string {
+	var pos string
+	if e.Pos != nil {
+		pos = " (" + e.Pos.String() + ")"
+	}
+	if e.Segment == "" {
+		return "table: call of " + e.Method + " on path " + strconv.Quote(e.Path) + pos + ": " + e.Err.Error()
+	}
+	return "table: call of " + e.Method + " at " + strconv.Quote(e.Segment) + " of path " + strconv.Quote(e.Path) + pos + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error of segment.
+func (e *ErrPath) Unwrap() error {
+	return e.Err
+}
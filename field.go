@@ -0,0 +1,145 @@
+package value
+
+import (
+	"reflect"
+	"sort"
+)
+
+// structField is a field of struct for conversion,
+// which may be promoted from the squashed embedded structs.
+type structField struct {
+	reflect.StructField
+
+	index  []int  // index sequence from the outermost struct
+	key    string // tag name, or field name if it's not tagged
+	tagged bool
+	passed bool // passed by tag `value:"-"`
+	remain bool // receives the unmatched keys by tag `value:",remain"`
+}
+
+// structFields returns the fields of struct type t for conversion.
+//
+// The fields of embedded struct tagged with `value:",squash"` or `value:",inline"`
+// are promoted to t, the embedded struct can be a pointer that is allocated when needed.
+// The other embedded structs are fields named by their type name.
+// The key of field is the name of value tag, or json, yaml and mapstructure tags in turn.
+// If the keys of fields are conflicting, the shallower field takes precedence,
+// and then the first declared one at the same depth.
+func structFields(t reflect.Type) []structField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []structField
+	seen := map[string]bool{}
+	visited := map[reflect.Type]bool{t: true}
+	for current := []embedded{{t, nil}}; len(current) > 0; {
+		var next []embedded
+		for _, e := range current {
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				name, opts := parseTag(fieldTag(sf))
+				index := append(e.index[:len(e.index):len(e.index)], i)
+
+				if sf.Anonymous && (opts.Has("squash") || opts.Has("inline")) {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr && sf.PkgPath == "" {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						if !visited[ft] {
+							visited[ft] = true
+							next = append(next, embedded{ft, index})
+						}
+						continue
+					}
+				}
+				if sf.PkgPath != "" { // unexported
+					continue
+				}
+
+				f := structField{StructField: sf, index: index, key: name, tagged: name != "", remain: opts.Has("remain")}
+				if name == "-" {
+					f.key, f.tagged, f.passed = sf.Name, false, true
+				} else if name == "" {
+					f.key = sf.Name
+				}
+				if seen[f.key] {
+					continue
+				}
+				seen[f.key] = true
+				fields = append(fields, f)
+			}
+		}
+		current = next
+	}
+	return fields
+}
+
+// fieldByIndex returns the field of struct v with index sequence.
+// The nil embedded pointers are allocated if alloc, otherwise it returns an invalid value.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// entry is a keyed value of map or struct.
+type entry struct {
+	name string // key as string
+	key  reflect.Value
+	val  reflect.Value
+}
+
+// entriesOf returns the entries of map or struct src sorted by name,
+// the struct fields are named with their keys, and the remain field is spread.
+func entriesOf(src reflect.Value) ([]entry, error) {
+	var entries []entry
+	addMap := func(rv reflect.Value) error {
+		iter := rv.MapRange()
+		for iter.Next() {
+			name, err := (&Value{rv: iter.Key()}).String()
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{name, iter.Key(), iter.Value()})
+		}
+		return nil
+	}
+
+	if src.Kind() == reflect.Map {
+		if err := addMap(src); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, f := range structFields(src.Type()) {
+			fv := fieldByIndex(src, f.index, false)
+			if f.passed || !fv.IsValid() {
+				continue
+			}
+			if f.remain && fv.Kind() == reflect.Map {
+				if err := addMap(fv); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			entries = append(entries, entry{f.key, reflect.ValueOf(f.key), fv})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries, nil
+}
@@ -0,0 +1,147 @@
+package value
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type FieldBase struct {
+	ID   int
+	Name string
+}
+
+type FieldMeta struct {
+	Name  string
+	Owner string `value:"owner,default=root"`
+}
+
+type fieldSquashed struct {
+	FieldBase  `value:",squash"`
+	*FieldMeta `value:",inline"`
+	Kind       string
+}
+
+type fieldNested struct {
+	FieldBase
+	Kind string
+}
+
+var _ = Describe("Embedded", func() {
+	Context("with structFields()", func() {
+		Specify("promoted and conflicting fields", func() {
+			var keys []string
+			for _, f := range structFields(reflect.TypeOf(fieldSquashed{})) {
+				keys = append(keys, f.key)
+			}
+			// Name is in both FieldBase and FieldMeta, the first declared wins
+			Expect(keys).Should(Equal([]string{"Kind", "ID", "Name", "owner"}))
+		})
+	})
+
+	Specify("squash embedded structs", func() {
+		x := map[string]interface{}{
+			"id":   1,
+			"name": "a",
+			"kind": "k",
+		}
+		var y fieldSquashed
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.ID).Should(Equal(1))
+		Expect(y.FieldBase.Name).Should(Equal("a"))
+		Expect(y.Kind).Should(Equal("k"))
+		Expect(y.FieldMeta).ShouldNot(BeNil())
+		Expect(y.FieldMeta.Name).Should(Equal(""))
+		Expect(y.Owner).Should(Equal("root"))
+	})
+	Specify("parent field takes precedence", func() {
+		type withKind struct {
+			FieldBase `value:",squash"`
+			ID        string
+		}
+		var y withKind
+		Expect(New(map[string]interface{}{"id": "x"}).ConvTo(&y)).Should(BeNil())
+		Expect(y.ID).Should(Equal("x"))
+		Expect(y.FieldBase.ID).Should(Equal(0))
+	})
+	Specify("nested embedded struct", func() {
+		x := map[string]interface{}{
+			"fieldbase": map[string]interface{}{"id": 1},
+			"id":        2,
+			"kind":      "k",
+		}
+		var y fieldNested
+		var meta Metadata
+		Expect(New(x).ConvToWithOptions(&y, ConvOptions{Metadata: &meta})).Should(BeNil())
+		Expect(y.ID).Should(Equal(1))
+		Expect(y.Kind).Should(Equal("k"))
+		Expect(meta.Unused).Should(Equal([]string{"id"}))
+	})
+	Specify("to generic with squash", func() {
+		x := fieldSquashed{FieldBase: FieldBase{1, "a"}, Kind: "k"}
+		Expect(New(x).ToGeneric()).Should(Equal(map[string]interface{}{
+			"ID": 1, "Name": "a", "Kind": "k",
+		}))
+	})
+})
+
+var _ = Describe("Remain", func() {
+	x := map[string]interface{}{
+		"name":  "gzip",
+		"kind":  "filter",
+		"level": 9,
+	}
+
+	Specify("to map[string]interface{}", func() {
+		var y struct {
+			Name  string
+			Extra map[string]interface{} `value:",remain"`
+		}
+		var meta Metadata
+		opts := ConvOptions{ErrorUnused: true, Metadata: &meta}
+		Expect(New(x).ConvToWithOptions(&y, opts)).Should(BeNil())
+		Expect(y.Name).Should(Equal("gzip"))
+		Expect(y.Extra).Should(Equal(map[string]interface{}{"kind": "filter", "level": 9}))
+		Expect(meta.Unused).Should(BeEmpty())
+		Expect(meta.Unset).Should(BeEmpty())
+	})
+	Specify("to map[string]*Value", func() {
+		var y struct {
+			Name  string
+			Extra map[string]*Value `value:",remain"`
+		}
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.Extra).Should(HaveLen(2))
+		Expect(y.Extra["level"].MustInt()).Should(Equal(9))
+	})
+	Specify("nothing remains", func() {
+		var y struct {
+			Name, Kind string
+			Level      int
+			Extra      map[string]interface{} `value:",remain"`
+		}
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.Extra).Should(BeNil())
+	})
+	Specify("failed", func() {
+		var y struct {
+			Name  string
+			Extra map[string]int `value:"extra,remain"`
+		}
+		err := New(x).ConvTo(&y)
+		Expect(err).Should(BeAssignableToTypeOf((*ErrMulti)(nil)))
+		Expect(err.(*ErrMulti).Errs).Should(HaveLen(1))
+		Expect(err.(*ErrMulti).Errs[0].(*ErrPath).Path).Should(Equal("kind"))
+		Expect(y.Extra).Should(Equal(map[string]int{"level": 9}))
+
+		var z struct {
+			Extra []string `value:"extra,remain"`
+		}
+		err = New(x).ConvTo(&z)
+		Expect(err).Should(BeAssignableToTypeOf((*ErrMulti)(nil)))
+		e := err.(*ErrMulti).Errs[0].(*ErrPath)
+		Expect(e.Path).Should(Equal("extra"))
+		Expect(e.Err).Should(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
+	})
+})
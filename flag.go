@@ -0,0 +1,42 @@
+package value
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+)
+
+// FromFlagSet new a Value from the flags of fs which have been set,
+// the names are split by '.' into nested keys, such as -db.host=x is {"db": {"host": "x"}}.
+//
+// The values are got by flag.Getter if implemented, like the bool, int and duration flags,
+// otherwise they are the strings of flag.Value.
+// It returns ErrPath if a name has an empty key like -db..host,
+// or is both a value and a parent of others, like -db and -db.host.
+func FromFlagSet(fs *flag.FlagSet) (*Value, error) {
+	root := reflect.ValueOf(map[string]interface{}{})
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+
+		var val interface{} = f.Value.String()
+		if g, ok := f.Value.(flag.Getter); ok {
+			val = g.Get()
+		}
+		var segs []pathSeg
+		for _, key := range strings.Split(f.Name, ".") {
+			segs = append(segs, pathSeg{key: key})
+		}
+		if err = putTree(root, segs, val); err != nil {
+			if e, ok := err.(*ErrPath); ok {
+				e.Method, e.Path = "value.FromFlagSet", f.Name
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return New(root.Interface()), nil
+}
@@ -0,0 +1,59 @@
+package value
+
+import (
+	"flag"
+	"io/ioutil"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FromFlagSet", func() {
+	newFlagSet := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.String("db.host", "localhost", "")
+		fs.Int("db.port", 5432, "")
+		fs.Duration("timeout", time.Second, "")
+		fs.Bool("debug", false, "")
+		return fs
+	}
+
+	Specify("with set flags", func() {
+		fs := newFlagSet()
+		Expect(fs.Parse([]string{"-db.host=x", "-timeout=30s", "-debug"})).Should(Succeed())
+
+		v, err := FromFlagSet(fs)
+		Expect(err).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{
+			"db":      map[string]interface{}{"host": "x"},
+			"timeout": 30 * time.Second,
+			"debug":   true,
+		}))
+
+		y := struct {
+			DB struct {
+				Host string
+				Port int
+			}
+			Timeout time.Duration
+			Debug   bool
+		}{}
+		y.DB.Port = 5432
+		Expect(v.ConvTo(&y)).Should(BeNil())
+		Expect(y.DB.Host).Should(Equal("x"))
+		Expect(y.DB.Port).Should(Equal(5432))
+		Expect(y.Timeout).Should(Equal(30 * time.Second))
+		Expect(y.Debug).Should(BeTrue())
+	})
+	Specify("with conflicting names", func() {
+		fs := newFlagSet()
+		fs.String("db", "", "")
+		Expect(fs.Parse([]string{"-db=x", "-db.host=y"})).Should(Succeed())
+
+		_, err := FromFlagSet(fs)
+		Expect(err).Should(BeAssignableToTypeOf((*ErrPath)(nil)))
+		Expect(err.(*ErrPath).Path).Should(Equal("db.host"))
+	})
+})
@@ -0,0 +1,349 @@
+package value
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatter formats value to the canonical string, that's parseable by ConvTo.
+// The composites are formatted as JSON, which struct keys are same as ConvTo,
+// and the scalars in composites are formatted like Value.String,
+// except the json.Marshaler and json.Number which are kept as is,
+// and the bytes which are encoded as base64 like encoding/json.
+// The time.Time of struct field is formatted with the layout or epoch of tag, like ConvTo parses it.
+type formatter struct {
+	buf      bytes.Buffer
+	visiting map[visit]bool
+	timeFmt  timeFormat // of current struct field
+}
+
+// formatComposite returns the JSON of map, slice, array or struct rv.
+func formatComposite(rv reflect.Value) (string, error) {
+	f := &formatter{visiting: map[visit]bool{}}
+	if err := f.value(rv); err != nil {
+		return "", err
+	}
+	return f.buf.String(), nil
+}
+
+// formatFloat formats f with the smallest number of digits necessary to represent it in bitSize.
+func formatFloat(f float64, bitSize int) string {
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+// formatComplex formats c like "(1+2i)" with the parts formatted by formatFloat in bitSize/2.
+func formatComplex(c complex128, bitSize int) string {
+	im := formatFloat(imag(c), bitSize/2)
+	if im[0] != '+' && im[0] != '-' {
+		im = "+" + im
+	}
+	return "(" + formatFloat(real(c), bitSize/2) + im + "i)"
+}
+
+// parseComplex parses s formatted like "(1+2i)", "1+2i", "2i" or "1" to complex in bitSize.
+func parseComplex(s string, bitSize int) (complex128, error) {
+	if len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+		s = s[1 : len(s)-1]
+	}
+	if !strings.HasSuffix(s, "i") {
+		re, err := strconv.ParseFloat(s, bitSize/2)
+		return complex(re, 0), err
+	}
+
+	s = s[:len(s)-1]
+	split := 0 // of the sign of imaginary part
+	for i := len(s) - 1; i > 0; i-- {
+		if (s[i] == '+' || s[i] == '-') && !strings.ContainsRune("eEpP", rune(s[i-1])) {
+			split = i
+			break
+		}
+	}
+	var re float64
+	if split > 0 {
+		var err error
+		if re, err = strconv.ParseFloat(s[:split], bitSize/2); err != nil {
+			return 0, err
+		}
+	}
+	im := s[split:]
+	switch im {
+	case "", "+", "-":
+		im += "1"
+	case "+NaN", "-NaN": // formatted with sign, but ParseFloat accepts no sign of NaN
+		im = im[1:]
+	}
+	x, err := strconv.ParseFloat(im, bitSize/2)
+	return complex(re, x), err
+}
+
+func (f *formatter) value(rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		f.buf.WriteString("null")
+		return nil
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			f.buf.WriteString("null")
+			return nil
+		}
+	}
+
+	if rv.CanInterface() {
+		switch iv := rv.Interface().(type) {
+		case time.Time:
+			f.time(iv)
+			return nil
+		case *time.Time:
+			f.time(*iv)
+			return nil
+		case *Value:
+			return f.value(iv.getrv())
+		case json.Number:
+			if _, err := strconv.ParseFloat(string(iv), 64); err == nil && json.Valid([]byte(iv)) {
+				f.buf.WriteString(string(iv))
+			} else {
+				f.quote(string(iv))
+			}
+			return nil
+		case json.Marshaler:
+			b, err := iv.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			return json.Compact(&f.buf, b)
+		case fmt.Stringer:
+			f.quote(iv.String())
+			return nil
+		case encoding.TextMarshaler:
+			text, err := iv.MarshalText()
+			if err != nil {
+				return err
+			}
+			f.quote(string(text))
+			return nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		f.buf.WriteString(strconv.FormatBool(rv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.buf.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f.buf.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		x := rv.Float()
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			f.quote(formatFloat(x, rv.Type().Bits()))
+		} else {
+			f.buf.WriteString(formatFloat(x, rv.Type().Bits()))
+		}
+	case reflect.Complex64, reflect.Complex128:
+		f.quote(formatComplex(rv.Complex(), rv.Type().Bits()))
+	case reflect.String:
+		f.quote(rv.String())
+
+	case reflect.Interface:
+		return f.value(rv.Elem())
+	case reflect.Ptr:
+		return f.enter(rv, func() error {
+			return f.value(rv.Elem())
+		})
+	case reflect.Map:
+		return f.enter(rv, func() error {
+			return f.mapValue(rv)
+		})
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			f.quote(base64.StdEncoding.EncodeToString(bytesOf(rv)))
+			return nil
+		}
+		f.buf.WriteByte('[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				f.buf.WriteByte(',')
+			}
+			if err := f.value(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		f.buf.WriteByte(']')
+	case reflect.Struct:
+		return f.structValue(rv)
+
+	default:
+		return &ErrUnsupportedKind{"Value.String", rv.Kind()}
+	}
+	return nil
+}
+
+// enter calls fn with rv marked as visiting, it returns ErrCyclic if rv is visiting.
+func (f *formatter) enter(rv reflect.Value, fn func() error) error {
+	key := visit{rv.Type(), rv.Pointer()}
+	if f.visiting[key] {
+		return &ErrCyclic{"Value.String"}
+	}
+	f.visiting[key] = true
+	defer delete(f.visiting, key)
+	return fn()
+}
+
+func (f *formatter) time(t time.Time) {
+	if s, quoted := f.timeFmt.format(t); quoted {
+		f.quote(s)
+	} else {
+		f.buf.WriteString(s)
+	}
+}
+
+func (f *formatter) quote(s string) {
+	enc := json.NewEncoder(&f.buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	f.buf.Truncate(f.buf.Len() - 1) // trailing newline
+}
+
+// object writes the key value pairs as JSON object in order of keys,
+// the values are formatted with their time formats in tfs if any.
+func (f *formatter) object(keys []string, vals map[string]reflect.Value, tfs map[string]timeFormat) error {
+	base := f.timeFmt
+	defer func() { f.timeFmt = base }()
+
+	f.buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			f.buf.WriteByte(',')
+		}
+		f.quote(key)
+		f.buf.WriteByte(':')
+		f.timeFmt = base
+		if tf, ok := tfs[key]; ok {
+			f.timeFmt = tf
+		}
+		if err := f.value(vals[key]); err != nil {
+			return err
+		}
+	}
+	f.buf.WriteByte('}')
+	return nil
+}
+
+func (f *formatter) mapValue(rv reflect.Value) error {
+	keys := make([]string, 0, rv.Len())
+	vals := make(map[string]reflect.Value, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key := (&Value{rv: iter.Key()}).keyString()
+		keys = append(keys, key)
+		vals[key] = iter.Value()
+	}
+	sort.Strings(keys)
+	return f.object(keys, vals, nil)
+}
+
+// structValue writes the struct fields keyed as structFields with their time formats,
+// and the entries of remain field.
+func (f *formatter) structValue(rv reflect.Value) error {
+	// the time format of tag applies to the field itself but not the nested fields
+	defer func(tf timeFormat) { f.timeFmt = tf }(f.timeFmt)
+	f.timeFmt = timeFormat{}
+
+	var keys []string
+	vals := map[string]reflect.Value{}
+	tfs := map[string]timeFormat{}
+	var remain reflect.Value
+	for _, sf := range structFields(rv.Type()) {
+		if sf.passed {
+			continue
+		}
+		fv := fieldByIndex(rv, sf.index, false)
+		if !fv.IsValid() {
+			continue
+		}
+		if sf.remain {
+			if !remain.IsValid() && fv.Kind() == reflect.Map && fv.Type().Key().Kind() == reflect.String {
+				remain = fv
+			}
+			continue
+		}
+		tf, err := f.timeFmt.field(sf.StructField)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, sf.key)
+		vals[sf.key] = fv
+		tfs[sf.key] = tf
+	}
+
+	if remain.IsValid() {
+		var extra []string
+		iter := remain.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if _, ok := vals[key]; !ok {
+				extra = append(extra, key)
+				vals[key] = iter.Value()
+			}
+		}
+		sort.Strings(extra)
+		keys = append(keys, extra...)
+	}
+	return f.object(keys, vals, tfs)
+}
+
+// bytesOf returns the bytes of byte slice or array rv.
+func bytesOf(rv reflect.Value) []byte {
+	if rv.Kind() == reflect.Slice {
+		return rv.Bytes()
+	}
+	b := make([]byte, rv.Len())
+	reflect.Copy(reflect.ValueOf(b), rv)
+	return b
+}
+
+// parseComposite parses the JSON s to generic tree for converting to composites,
+// the numbers are parsed as int64, uint64 or float64 in turn, and kept as json.Number if overflow.
+func parseComposite(s string) (interface{}, error) {
+	x, err := decodeJSON(strings.NewReader(s), true)
+	if err != nil {
+		return nil, err
+	}
+	return fromNumbers(x), nil
+}
+
+// fromNumbers replaces json.Number in x with int64, uint64 or float64,
+// except the integers overflow uint64.
+func fromNumbers(x interface{}) interface{} {
+	switch x := x.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(x), 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+			return u
+		}
+		if !strings.ContainsAny(string(x), ".eE") {
+			return x // big integer
+		}
+		f, _ := strconv.ParseFloat(string(x), 64)
+		return f
+	case map[string]interface{}:
+		for k, v := range x {
+			x[k] = fromNumbers(v)
+		}
+	case []interface{}:
+		for i, v := range x {
+			x[i] = fromNumbers(v)
+		}
+	}
+	return x
+}
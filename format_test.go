@@ -0,0 +1,149 @@
+package value
+
+import (
+	"math"
+	"net"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type formatTestInner struct {
+	At   time.Time
+	IP   net.IP
+	Wait time.Duration
+}
+
+type formatTestOuter struct {
+	Name            string
+	Port            int `json:"listen_port"`
+	Ratio           float32
+	C               complex64
+	Tags            []string
+	Raw             []byte
+	Arr             [2]uint8
+	Inner           *formatTestInner
+	Nested          map[string][]int
+	Any             interface{}
+	Skip            string                 `value:"-"`
+	Extra           map[string]interface{} `value:",remain"`
+	formatTestInner `value:",squash"`
+}
+
+var _ = Describe("Format", func() {
+	Specify("scalars", func() {
+		Expect(New(float32(0.1)).String()).Should(Equal("0.1"))
+		Expect(New(0.1).String()).Should(Equal("0.1"))
+		Expect(New(complex64(0.1 + 0.2i)).String()).Should(Equal("(0.1+0.2i)"))
+		Expect(New([]byte("abc")).String()).Should(Equal("abc"))
+		Expect(New([3]byte{'a', 'b', 'c'}).String()).Should(Equal("abc"))
+	})
+	Specify("composites", func() {
+		x := map[interface{}]interface{}{
+			"b": []interface{}{1, "x<y", nil, math.Inf(1)},
+			1:   struct{ A, b int }{A: 1},
+			"a": map[string]float32{"f": 0.1},
+		}
+		Expect(New(x).String()).Should(Equal(`{"1":{"A":1},"a":{"f":0.1},"b":[1,"x<y",null,"+Inf"]}`))
+
+		type cyclic struct{ P *cyclic }
+		c := &cyclic{}
+		c.P = c
+		_, err := New(c).String()
+		Expect(err).Should(BeAssignableToTypeOf((*ErrCyclic)(nil)))
+	})
+	Specify("round trip", func() {
+		at := time.Date(2019, 11, 1, 19, 13, 55, 1, time.UTC)
+		x := formatTestOuter{
+			Name:   "n\"ame",
+			Port:   80,
+			Ratio:  0.1,
+			C:      1 + 2i,
+			Tags:   []string{"a", "b"},
+			Raw:    []byte{0, 1, 'a'},
+			Arr:    [2]uint8{'x', 'y'},
+			Inner:  &formatTestInner{At: at, IP: net.ParseIP("8.8.8.8"), Wait: time.Second},
+			Nested: map[string][]int{"a": {1, 2}, "b": nil},
+			Any:    map[string]interface{}{"big": uint64(math.MaxUint64), "f": 1.5},
+			Skip:   "skip",
+			Extra:  map[string]interface{}{"opt": "x"},
+		}
+		x.Wait = time.Minute
+
+		s, err := New(x).String()
+		Expect(err).Should(BeNil())
+
+		var y formatTestOuter
+		Expect(ConvTo(s, &y)).Should(BeNil(), s)
+		x.Skip = ""
+		Expect(reflect.DeepEqual(x, y)).Should(BeTrue(), s)
+
+		for _, x := range []interface{}{
+			float32(math.Pi), math.MaxFloat64, 1 + 2i, []int{1, 2}, [2]bool{true},
+			map[string]float32{"a": 0.3}, map[int]string{1: "a"}, []byte("bytes"),
+		} {
+			y := reflect.New(reflect.TypeOf(x))
+			Expect(ConvTo(New(x).MustString(), y.Interface())).Should(BeNil(), "%v", x)
+			Expect(y.Elem().Interface()).Should(Equal(x))
+		}
+	})
+	Specify("bytes in composites", func() {
+		x := struct {
+			B []byte
+			A [2]byte
+			M map[string][]byte
+		}{[]byte{0xff, 0xfe, 'a'}, [2]byte{0x80, 0}, map[string][]byte{"k": {0xc3, 0x28}}}
+		s, err := New(x).String()
+		Expect(err).Should(BeNil())
+		Expect(s).Should(Equal(`{"B":"//5h","A":"gAA=","M":{"k":"wyg="}}`))
+
+		y := x
+		y.B, y.A, y.M = nil, [2]byte{}, nil
+		Expect(ConvTo(s, &y)).Should(BeNil())
+		Expect(y).Should(Equal(x))
+
+		var b []byte
+		Expect(ConvTo(string([]byte{0xff, 'a'}), &b)).Should(BeNil())
+		Expect(b).Should(Equal([]byte{0xff, 'a'}))
+		Expect(ConvTo(`{"B":"not base64"}`, &y)).ShouldNot(BeNil())
+	})
+	Specify("complex", func() {
+		for s, c := range map[string]complex128{
+			"(1+2i)": 1 + 2i, "1-2i": 1 - 2i, "2i": 2i, "-i": -1i, "1.5": 1.5,
+			"(1e+2-3.5e-1i)": complex(100, -0.35), "(+Inf+NaNi)": complex(math.Inf(1), math.NaN()),
+		} {
+			x, err := parseComplex(s, 128)
+			Expect(err).Should(BeNil(), s)
+			if math.IsNaN(imag(c)) {
+				Expect(real(x)).Should(Equal(real(c)), s)
+				Expect(math.IsNaN(imag(x))).Should(BeTrue(), s)
+				continue
+			}
+			Expect(x).Should(Equal(c), s)
+		}
+		for _, s := range []string{"", "i1", "(1+2)", "1+2j", "(1+2i"} {
+			_, err := parseComplex(s, 128)
+			Expect(err).ShouldNot(BeNil(), s)
+		}
+		Expect(formatComplex(complex(1, math.Inf(-1)), 128)).Should(Equal("(1-Infi)"))
+		Expect(formatComplex(complex128(complex64(0.1-0.2i)), 64)).Should(Equal("(0.1-0.2i)"))
+	})
+	Specify("to existing map", func() {
+		y := map[int]int{1: 1, 2: 2}
+		Expect(ConvTo(`{"1":10,"3":30}`, &y)).Should(BeNil())
+		Expect(y).Should(Equal(map[int]int{1: 10, 2: 2, 3: 30}))
+		Expect(ConvTo(`{"x":1}`, &y)).ShouldNot(BeNil())
+	})
+	Specify("parse failed", func() {
+		var y map[string]int
+		Expect(ConvTo(`{"a":1`, &y)).ShouldNot(BeNil())
+		Expect(ConvTo(`{"a":1} {}`, &y)).ShouldNot(BeNil())
+
+		var z []string
+		Expect(ConvTo("a", &z)).ShouldNot(BeNil())
+		Expect(New("a").ConvToWithOptions(&z, ConvOptions{WeaklyTyped: true})).Should(BeNil())
+		Expect(z).Should(Equal([]string{"a"}))
+	})
+})
@@ -0,0 +1,179 @@
+package value
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// GenericOptions is the options of Value.ToGenericWithOptions.
+type GenericOptions struct {
+	// OmitEmpty omits all empty struct fields, same as the tag option "omitempty".
+	OmitEmpty bool
+
+	// FlattenEmbedded puts the fields of all embedded structs into the parent,
+	// instead of nesting them with the type name,
+	// same as the tag option "squash" or "inline" of embedded struct.
+	// The fields of parent take precedence over the embedded ones.
+	FlattenEmbedded bool
+}
+
+// ToGeneric returns t's underlying value as a generic tree,
+// that consists of map[string]interface{}, []interface{} and scalars.
+//
+// The struct is converted to map[string]interface{} with its exported fields,
+// the field name can be set with tag `value:"name"`, and be omitted with tag `value:"-"`,
+// or with tag `value:",omitempty"` if it's empty. The fields are keyed as ConvTo matches them,
+// with the json, yaml and mapstructure tags as fallback, the squashed embedded structs flattened,
+// and the keys of remain field spread into the struct.
+// The map keys are converted to string with Value.String.
+// The value implements encoding.TextMarshaler and []byte are kept as scalars.
+// It returns ErrCyclic if the pointers or maps are cyclic.
+func (v *Value) ToGeneric() (interface{}, error) {
+	return v.ToGenericWithOptions(GenericOptions{})
+}
+
+// ToGenericWithOptions same as ToGeneric with options.
+func (v *Value) ToGenericWithOptions(opts GenericOptions) (interface{}, error) {
+	g := &generic{opts: opts, visiting: map[visit]bool{}}
+	return g.value(v.getrv())
+}
+
+// visit is a visiting pointer with its type.
+type visit struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+type generic struct {
+	opts     GenericOptions
+	visiting map[visit]bool
+}
+
+func (g *generic) value(rv reflect.Value) (interface{}, error) {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if rv.CanInterface() {
+		if _, ok := rv.Interface().(encoding.TextMarshaler); ok {
+			return rv.Interface(), nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		return g.value(rv.Elem())
+
+	case reflect.Ptr:
+		return g.enter(rv, func() (interface{}, error) {
+			return g.value(rv.Elem())
+		})
+
+	case reflect.Map:
+		return g.enter(rv, func() (interface{}, error) {
+			return g.mapValue(rv)
+		})
+
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface(), nil
+		}
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			ev, err := g.value(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			s[i] = ev
+		}
+		return s, nil
+
+	case reflect.Struct:
+		return g.structValue(rv)
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil, &ErrUnsupportedKind{"Value.ToGeneric", rv.Kind()}
+
+	default:
+		return rv.Interface(), nil
+	}
+}
+
+// enter calls f with rv marked as visiting, it returns ErrCyclic if rv is visiting.
+func (g *generic) enter(rv reflect.Value, f func() (interface{}, error)) (interface{}, error) {
+	key := visit{rv.Type(), rv.Pointer()}
+	if g.visiting[key] {
+		return nil, &ErrCyclic{"Value.ToGeneric"}
+	}
+	g.visiting[key] = true
+	defer delete(g.visiting, key)
+	return f()
+}
+
+func (g *generic) mapValue(rv reflect.Value) (interface{}, error) {
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := (&Value{rv: iter.Key()}).String()
+		if err != nil {
+			return nil, err
+		}
+		val, err := g.value(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		m[key] = val
+	}
+	return m, nil
+}
+
+func (g *generic) structValue(rv reflect.Value) (interface{}, error) {
+	fields := structFields(rv.Type())
+	m := make(map[string]interface{}, len(fields))
+	var embeddeds []map[string]interface{}
+	for _, f := range fields {
+		fv := fieldByIndex(rv, f.index, false)
+		if f.passed || !fv.IsValid() {
+			continue
+		}
+		if f.remain && fv.Kind() == reflect.Map {
+			val, err := g.mapValue(fv)
+			if err != nil {
+				return nil, err
+			}
+			embeddeds = append(embeddeds, val.(map[string]interface{}))
+			continue
+		}
+		_, opts := parseTag(fieldTag(f.StructField))
+		if (g.opts.OmitEmpty || opts.Has("omitempty")) && isEmptyValue(fv) {
+			continue
+		}
+		val, err := g.value(fv)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Anonymous && !f.tagged && g.opts.FlattenEmbedded {
+			if em, ok := val.(map[string]interface{}); ok {
+				embeddeds = append(embeddeds, em)
+				continue
+			}
+		}
+		m[f.key] = val
+	}
+
+	for _, em := range embeddeds {
+		for key, val := range em {
+			if _, ok := m[key]; !ok {
+				m[key] = val
+			}
+		}
+	}
+	return m, nil
+}
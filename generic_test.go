@@ -0,0 +1,124 @@
+package value
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type GenericBase struct {
+	ID   int
+	Name string
+}
+
+type genericHidden struct {
+	Hidden int
+}
+
+type genericSquash struct {
+	Port int    `json:"port"`
+	Name string `json:"name"`
+}
+
+type genericNode struct {
+	GenericBase
+	genericHidden
+	Name   string `value:"name"`
+	Skip   string `value:"-"`
+	Empty  string `value:",omitempty"`
+	At     time.Time
+	Tags   map[int]string
+	Next   *genericNode
+	Bytes  []byte
+	hidden int
+}
+
+var _ = Describe("ToGeneric", func() {
+	at := time.Date(2019, 11, 1, 19, 13, 55, 0, time.UTC)
+	x := &genericNode{
+		GenericBase:   GenericBase{ID: 1, Name: "base"},
+		genericHidden: genericHidden{1},
+		Name:          "a",
+		Skip:          "skip",
+		At:            at,
+		Tags:          map[int]string{1: "one"},
+		Next:          &genericNode{Name: "b"},
+		Bytes:         []byte("b"),
+	}
+
+	Specify("with default options", func() {
+		g, err := New(x).ToGeneric()
+		Expect(err).Should(BeNil())
+		m := g.(map[string]interface{})
+		Expect(m["GenericBase"]).Should(Equal(map[string]interface{}{"ID": 1, "Name": "base"}))
+		Expect(m["name"]).Should(Equal("a"))
+		Expect(m).ShouldNot(HaveKey("Skip"))
+		Expect(m).ShouldNot(HaveKey("Empty"))
+		Expect(m).ShouldNot(HaveKey("hidden"))
+		Expect(m).ShouldNot(HaveKey("genericHidden"))
+		Expect(m).ShouldNot(HaveKey("Hidden"))
+		Expect(m["At"]).Should(Equal(at))
+		Expect(m["Tags"]).Should(Equal(map[string]interface{}{"1": "one"}))
+		Expect(m["Bytes"]).Should(Equal([]byte("b")))
+		Expect(m["Next"].(map[string]interface{})["name"]).Should(Equal("b"))
+		Expect(m["Next"].(map[string]interface{})["Next"]).Should(BeNil())
+	})
+	Specify("with omitempty and flatten embedded", func() {
+		g, err := New(x).ToGenericWithOptions(GenericOptions{OmitEmpty: true, FlattenEmbedded: true})
+		Expect(err).Should(BeNil())
+		m := g.(map[string]interface{})
+		Expect(m["ID"]).Should(Equal(1))
+		Expect(m["Name"]).Should(Equal("base"))
+		Expect(m["name"]).Should(Equal("a"))
+		Expect(m).ShouldNot(HaveKey("GenericBase"))
+		Expect(m).ShouldNot(HaveKey("Hidden"))
+		Expect(m["Next"]).Should(Equal(map[string]interface{}{"name": "b", "At": time.Time{}}))
+	})
+	Specify("with squashed embedded structs", func() {
+		type squashed struct {
+			genericSquash `yaml:",inline"`
+			*GenericBase  `value:",squash"`
+			Name          string `value:"name"`
+		}
+		y := squashed{genericSquash{80, "squash"}, &GenericBase{ID: 2, Name: "base"}, "a"}
+		g, err := New(y).ToGeneric()
+		Expect(err).Should(BeNil())
+		Expect(g).Should(Equal(map[string]interface{}{"port": 80, "name": "a", "ID": 2, "Name": "base"}))
+
+		// converted back as ConvTo matches the fields
+		var z squashed
+		Expect(New(g).ConvTo(&z)).Should(BeNil())
+		Expect(z.Port).Should(Equal(80))
+		Expect(*z.GenericBase).Should(Equal(GenericBase{ID: 2, Name: "base"}))
+		Expect(z.Name).Should(Equal("a"))
+
+		y.GenericBase = nil
+		Expect(New(y).ToGeneric()).Should(Equal(map[string]interface{}{"port": 80, "name": "a"}))
+	})
+	Specify("with remain field", func() {
+		y := struct {
+			Name  string                 `value:"name"`
+			Extra map[string]interface{} `value:",remain"`
+		}{"a", map[string]interface{}{"x": 1, "name": "b"}}
+		g, err := New(y).ToGeneric()
+		Expect(err).Should(BeNil())
+		Expect(g).Should(Equal(map[string]interface{}{"name": "a", "x": 1}))
+		gs, _ := New(g).String()
+		ys, _ := New(y).String()
+		Expect(gs).Should(Equal(ys))
+	})
+	Specify("with slice and scalar", func() {
+		Expect(New([]interface{}{1, "a", nil}).ToGeneric()).Should(Equal([]interface{}{1, "a", nil}))
+		Expect(New(1.5).ToGeneric()).Should(Equal(1.5))
+		Expect(New(nil).ToGeneric()).Should(BeNil())
+	})
+	Specify("with cyclic value", func() {
+		y := &genericNode{}
+		y.Next = y
+		ExpectErr(New(y).ToGeneric()).To(BeAssignableToTypeOf((*ErrCyclic)(nil)))
+	})
+	Specify("with unsupported kind", func() {
+		ExpectErr(New(make(chan int)).ToGeneric()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
+	})
+})
@@ -1,7 +1,11 @@
 package value
 
 import (
+	"fmt"
+	"math"
+	"math/bits"
 	"reflect"
+	"strconv"
 )
 
 func (v *Value) getrv() reflect.Value {
@@ -13,12 +17,21 @@ func (v *Value) getrv() reflect.Value {
 }
 
 func (v *Value) getiv() interface{} {
-	if v.iv == nil {
+	if v.iv == nil && v.rv.IsValid() {
 		v.iv = v.rv.Interface()
 	}
 	return v.iv
 }
 
+// keyString returns t's underlying value as a string for the key of path.
+func (v *Value) keyString() string {
+	s, err := v.String()
+	if err != nil {
+		return fmt.Sprint(v.getiv())
+	}
+	return s
+}
+
 //// get op
 
 func (v *Value) mapGet(key interface{}) *Value {
@@ -97,6 +110,74 @@ func (v *Value) structPut(fn string, field interface{}) error {
 	return nil
 }
 
+//// delete op
+
+func (v *Value) mapDelete(key interface{}) error {
+	rv := v.getrv()
+	kv := reflect.ValueOf(key)
+	if !kv.IsValid() || !kv.Type().AssignableTo(rv.Type().Key()) || !rv.MapIndex(kv).IsValid() {
+		return &ErrNotExist{"Value.mapDelete", fmt.Sprint(key) + " key"}
+	}
+	rv.SetMapIndex(kv, reflect.Value{})
+	return nil
+}
+
+func (v *Value) arrayDelete(key interface{}) error {
+	rv := v.getrv()
+	idx, ok := key.(int)
+	if !ok || idx < 0 || idx >= rv.Len() {
+		return &ErrNotExist{"Value.arrayDelete", fmt.Sprint(key) + " index"}
+	}
+	ev := rv.Index(idx)
+	ev.Set(reflect.Zero(ev.Type()))
+	return nil
+}
+
+// sliceDelete removes the element of index key, the slice is set back if t is settable.
+func (v *Value) sliceDelete(key interface{}) error {
+	rv := v.getrv()
+	idx, ok := key.(int)
+	if !ok || idx < 0 || idx >= rv.Len() {
+		return &ErrNotExist{"Value.sliceDelete", fmt.Sprint(key) + " index"}
+	}
+
+	nv := removeIndex(rv, idx)
+	if rv.CanSet() {
+		rv.Set(nv)
+	}
+	v.rv = nv
+	v.iv = nil
+	return nil
+}
+
+func (v *Value) structDelete(key interface{}) error {
+	fn, _ := key.(string)
+	fv := v.getrv().FieldByName(fn)
+	if !fv.IsValid() {
+		return &ErrNotExist{"Value.structDelete", fmt.Sprint(key) + " field"}
+	}
+	if !fv.CanSet() {
+		return &ErrCannotSet{"Value.structDelete"}
+	}
+	fv.Set(reflect.Zero(fv.Type()))
+	return nil
+}
+
+// removeIndex removes the element at idx of slice rv, and returns the shortened slice.
+// If rv is settable, the elements after idx are shifted in place, and the last one is set to zero value,
+// otherwise they're copied to a new slice, so that the backing array of rv is unchanged.
+func removeIndex(rv reflect.Value, idx int) reflect.Value {
+	n := rv.Len()
+	if !rv.CanSet() {
+		nv := reflect.MakeSlice(rv.Type(), 0, n-1)
+		nv = reflect.AppendSlice(nv, rv.Slice(0, idx))
+		return reflect.AppendSlice(nv, rv.Slice(idx+1, n))
+	}
+	reflect.Copy(rv.Slice(idx, n), rv.Slice(idx+1, n))
+	rv.Index(n - 1).Set(reflect.Zero(rv.Type().Elem()))
+	return rv.Slice(0, n-1)
+}
+
 func (v *Value) bool() bool {
 	return v.getrv().Bool()
 }
@@ -121,6 +202,210 @@ func (v *Value) string() string {
 	return v.getrv().String()
 }
 
+//// number op
+
+// kindBits returns the size of numeric kind k in bits.
+func kindBits(k reflect.Kind) int {
+	switch k {
+	case reflect.Int8, reflect.Uint8:
+		return 8
+	case reflect.Int16, reflect.Uint16:
+		return 16
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 32
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return bits.UintSize
+	default:
+		return 64
+	}
+}
+
+// numeric returns t's underlying value with kind Int*, Uint* or Float*,
+// the numeric string is parsed as int64, uint64 or float64 in turn.
+func (v *Value) numeric(method string) (reflect.Value, error) {
+	rv := indirect(v.getrv())
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return rv, nil
+	case reflect.String:
+		s := rv.String()
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return reflect.ValueOf(i), nil
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return reflect.ValueOf(u), nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return reflect.ValueOf(f), nil
+		}
+		return rv, &ErrUnsupportedKind{method, "non-numeric string"}
+	default:
+		return rv, &ErrUnsupportedKind{method, rv.Kind()}
+	}
+}
+
+// toInt converts t's underlying value to an integer of kind.
+func (v *Value) toInt(method string, kind reflect.Kind) (int64, error) {
+	rv, err := v.numeric(method)
+	if err != nil {
+		return 0, err
+	}
+
+	n := uint(kindBits(kind))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if n < 64 && (i < -1<<(n-1) || i >= 1<<(n-1)) {
+			return 0, &ErrNumOverflow{method, kind, i}
+		}
+		return i, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u >= 1<<(n-1) {
+			return 0, &ErrNumOverflow{method, kind, u}
+		}
+		return int64(u), nil
+	default: // float
+		f := rv.Float()
+		if math.IsNaN(f) || f < -math.Ldexp(1, int(n-1)) || f >= math.Ldexp(1, int(n-1)) {
+			return 0, &ErrNumOverflow{method, kind, f}
+		}
+		if f != math.Trunc(f) {
+			return 0, &ErrTruncated{method, kind, f}
+		}
+		return int64(f), nil
+	}
+}
+
+// toUint converts t's underlying value to an unsigned integer of kind.
+func (v *Value) toUint(method string, kind reflect.Kind) (uint64, error) {
+	rv, err := v.numeric(method)
+	if err != nil {
+		return 0, err
+	}
+
+	n := uint(kindBits(kind))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if i < 0 || (n < 64 && i >= 1<<n) {
+			return 0, &ErrNumOverflow{method, kind, i}
+		}
+		return uint64(i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if n < 64 && u >= 1<<n {
+			return 0, &ErrNumOverflow{method, kind, u}
+		}
+		return u, nil
+	default: // float
+		f := rv.Float()
+		if math.IsNaN(f) || f < 0 || f >= math.Ldexp(1, int(n)) {
+			return 0, &ErrNumOverflow{method, kind, f}
+		}
+		if f != math.Trunc(f) {
+			return 0, &ErrTruncated{method, kind, f}
+		}
+		return uint64(f), nil
+	}
+}
+
+// toFloat converts t's underlying value to a float of kind.
+func (v *Value) toFloat(method string, kind reflect.Kind) (float64, error) {
+	rv, err := v.numeric(method)
+	if err != nil {
+		return 0, err
+	}
+
+	var f float64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		f = roundFloat(float64(i), kind)
+		if f >= math.Ldexp(1, 63) || int64(f) != i {
+			return 0, &ErrTruncated{method, kind, i}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		f = roundFloat(float64(u), kind)
+		if f >= math.Ldexp(1, 64) || uint64(f) != u {
+			return 0, &ErrTruncated{method, kind, u}
+		}
+	default: // float
+		f = rv.Float()
+	}
+	if kind == reflect.Float32 && !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+		return 0, &ErrNumOverflow{method, kind, f}
+	}
+	return f, nil
+}
+
+// roundFloat rounds f to the precision of float kind.
+func roundFloat(f float64, kind reflect.Kind) float64 {
+	if kind == reflect.Float32 {
+		return float64(float32(f))
+	}
+	return f
+}
+
+// isEmptyValue reports whether rv is false, 0, a nil pointer, a nil interface,
+// or an array, map, slice or string of length zero.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// isZero reports whether rv is the zero value of its type, like reflect.Value.IsZero of Go 1.13.
+func isZero(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return math.Float64bits(rv.Float()) == 0
+	case reflect.Complex64, reflect.Complex128:
+		c := rv.Complex()
+		return math.Float64bits(real(c)) == 0 && math.Float64bits(imag(c)) == 0
+	case reflect.String:
+		return rv.Len() == 0
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if !isZero(rv.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if !isZero(rv.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
 // func (v *Value) interface_() interface{} {
 // 	return v.getrv().Interface()
 // }
@@ -0,0 +1,78 @@
+package value
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// JSONOptions is the options of FromJSONWithOptions and FromJSONReaderWithOptions.
+type JSONOptions struct {
+	// UseNumber decodes the numbers as json.Number instead of float64,
+	// so that the numeric accessors and ConvTo can convert them exactly.
+	UseNumber bool
+}
+
+// FromJSON new a Value from JSON data,
+// which is decoded to map[string]interface{}, []interface{} and scalars as encoding/json.
+func FromJSON(data []byte) (*Value, error) {
+	return FromJSONWithOptions(data, JSONOptions{})
+}
+
+// FromJSONWithOptions same as FromJSON with options.
+func FromJSONWithOptions(data []byte, opts JSONOptions) (*Value, error) {
+	return FromJSONReaderWithOptions(bytes.NewReader(data), opts)
+}
+
+// FromJSONReader new a Value from the JSON read from r.
+// It reads the first JSON value, and returns error if there is any other but spaces.
+func FromJSONReader(r io.Reader) (*Value, error) {
+	return FromJSONReaderWithOptions(r, JSONOptions{})
+}
+
+// FromJSONReaderWithOptions same as FromJSONReader with options.
+func FromJSONReaderWithOptions(r io.Reader, opts JSONOptions) (*Value, error) {
+	x, err := decodeJSON(r, opts.UseNumber)
+	if err != nil {
+		return nil, err
+	}
+	return New(x), nil
+}
+
+func decodeJSON(r io.Reader, useNumber bool) (interface{}, error) {
+	dec := json.NewDecoder(r)
+	if useNumber {
+		dec.UseNumber()
+	}
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid character after top-level value")
+	}
+	return x, nil
+}
+
+// MarshalJSON implements json.Marshaler, the JSON is same as Value.String of composites,
+// that the struct keys are same as ConvTo. It has a value receiver to marshal Value fields by value.
+func (v Value) MarshalJSON() ([]byte, error) {
+	f := &formatter{visiting: map[visit]bool{}}
+	if err := f.value(v.getrv()); err != nil {
+		return nil, err
+	}
+	return f.buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the numbers are decoded as json.Number.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	x, err := decodeJSON(bytes.NewReader(data), true)
+	if err != nil {
+		return err
+	}
+	v.iv = x
+	v.rv = reflect.ValueOf(x)
+	return nil
+}
@@ -0,0 +1,95 @@
+package value
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON", func() {
+	data := `{"id": 12345678901234567890, "f": 0.5, "tags": ["a", null], "sub": {"ok": true}}`
+
+	Specify("FromJSON()", func() {
+		v, err := FromJSON([]byte(data))
+		Expect(err).Should(BeNil())
+		Expect(v.MustGetPath("f").MustFloat64()).Should(Equal(0.5))
+		Expect(v.MustGetPath("id").Interface()).Should(BeAssignableToTypeOf(float64(0)))
+		Expect(v.MustGetPath("tags[0]").MustString()).Should(Equal("a"))
+		Expect(v.MustGetPath("sub.ok").MustBool()).Should(BeTrue())
+
+		_, err = FromJSON([]byte(`{"a":1}}`))
+		Expect(err).ShouldNot(BeNil())
+		_, err = FromJSON([]byte(`{"a":`))
+		Expect(err).ShouldNot(BeNil())
+	})
+	Specify("FromJSONReader() with json.Number", func() {
+		v, err := FromJSONReaderWithOptions(strings.NewReader(data), JSONOptions{UseNumber: true})
+		Expect(err).Should(BeNil())
+		Expect(v.MustGetPath("id").Interface()).Should(Equal(json.Number("12345678901234567890")))
+		Expect(v.MustGetPath("id").MustUint64()).Should(Equal(uint64(12345678901234567890)))
+		_, err = v.MustGetPath("id").Int64()
+		Expect(err).Should(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+
+		var y struct {
+			ID uint64
+			F  float32
+		}
+		Expect(v.ConvTo(&y)).Should(BeNil())
+		Expect(y.ID).Should(Equal(uint64(12345678901234567890)))
+		Expect(y.F).Should(Equal(float32(0.5)))
+
+		v, err = FromJSONReader(strings.NewReader(" 1 \n"))
+		Expect(err).Should(BeNil())
+		Expect(v.MustInt()).Should(Equal(1))
+	})
+	Specify("MarshalJSON()", func() {
+		n := new(big.Int)
+		n.SetString("123456789012345678901234567890", 10)
+		x := map[interface{}]interface{}{
+			1:   []interface{}{json.Number("1.50"), n},
+			"v": New(struct{ A string }{"a"}),
+		}
+		b, err := json.Marshal(New(x))
+		Expect(err).Should(BeNil())
+		Expect(string(b)).Should(Equal(`{"1":[1.50,123456789012345678901234567890],"v":{"A":"a"}}`))
+
+		b, err = json.Marshal(New(nil))
+		Expect(err).Should(BeNil())
+		Expect(string(b)).Should(Equal(`null`))
+
+		var y struct{ N *big.Int }
+		Expect(ConvTo(New(map[string]interface{}{"n": n}).MustString(), &y)).Should(BeNil())
+		Expect(y.N).Should(Equal(n))
+	})
+	Specify("UnmarshalJSON() as struct field", func() {
+		var y struct {
+			Name    string
+			Options *Value
+			Raw     Value
+		}
+		Expect(json.Unmarshal([]byte(`{"Name":"a","Options":{"n":1e2,"s":"x"},"Raw":[1]}`), &y)).Should(BeNil())
+		Expect(y.Options.MustGet("n").MustInt()).Should(Equal(100))
+		Expect(y.Options.MustGet("s").MustString()).Should(Equal("x"))
+		Expect(y.Raw.MustGet(0).Interface()).Should(Equal(json.Number("1")))
+
+		b, err := json.Marshal(&y)
+		Expect(err).Should(BeNil())
+		Expect(string(b)).Should(Equal(`{"Name":"a","Options":{"n":1e2,"s":"x"},"Raw":[1]}`))
+
+		b, err = json.Marshal(y)
+		Expect(err).Should(BeNil())
+		Expect(string(b)).Should(Equal(`{"Name":"a","Options":{"n":1e2,"s":"x"},"Raw":[1]}`))
+
+		b, err = json.Marshal(struct {
+			Name string
+			V    Value
+		}{"a", *New(map[string]int{"n": 1})})
+		Expect(err).Should(BeNil())
+		Expect(string(b)).Should(Equal(`{"Name":"a","V":{"n":1}}`))
+
+		Expect(json.Unmarshal([]byte(`{"Options":{}}}`), &y)).ShouldNot(BeNil())
+	})
+})
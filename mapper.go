@@ -0,0 +1,76 @@
+package value
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldNameMapper normalizes the struct field names and the source keys,
+// a source key matches the field if they're normalized to the same.
+// The keys of struct tag are always matched exactly.
+type FieldNameMapper func(name string) string
+
+// MatchExact matches the field name exactly.
+func MatchExact(name string) string {
+	return name
+}
+
+// MatchCaseInsensitive matches the field name case-insensitively,
+// it's the default FieldNameMapper.
+func MatchCaseInsensitive(name string) string {
+	return strings.ToLower(name)
+}
+
+// MatchSnakeCase matches the field name in snake_case,
+// such that "max_conns" matches the field MaxConns.
+func MatchSnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// MatchKebabCase matches the field name in kebab-case,
+// such that "max-conns" matches the field MaxConns.
+func MatchKebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// MatchCamelCase matches the field name in camelCase,
+// such that "maxConns" matches the field MaxConns.
+func MatchCamelCase(name string) string {
+	words := splitWords(name)
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if i > 0 {
+			r, n := utf8.DecodeRuneInString(w)
+			w = string(unicode.ToUpper(r)) + w[n:]
+		}
+		words[i] = w
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits name into words at the separators '_', '-', '.' and space,
+// and at the case changes, such that "HTTPServer_v2" is split to "HTTP", "Server" and "v2".
+func splitWords(name string) []string {
+	var words []string
+	rs := []rune(name)
+	start := 0
+	for i := 0; i <= len(rs); i++ {
+		if i == len(rs) || rs[i] == '_' || rs[i] == '-' || rs[i] == '.' || rs[i] == ' ' {
+			if start < i {
+				words = append(words, string(rs[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i > start && unicode.IsUpper(rs[i]) {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(rs[start:i]))
+				start = i
+			}
+		}
+	}
+	return words
+}
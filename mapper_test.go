@@ -0,0 +1,123 @@
+package value
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FieldNameMapper", func() {
+	Specify("splitWords()", func() {
+		Expect(splitWords("HTTPServer_v2")).Should(Equal([]string{"HTTP", "Server", "v2"}))
+		Expect(splitWords("maxConns")).Should(Equal([]string{"max", "Conns"}))
+		Expect(splitWords("max-conns.x y")).Should(Equal([]string{"max", "conns", "x", "y"}))
+		Expect(splitWords("IPv6Addr")).Should(Equal([]string{"I", "Pv6", "Addr"}))
+		Expect(splitWords("")).Should(BeEmpty())
+	})
+	Specify("built-in mappers", func() {
+		Expect(MatchExact("MaxConns")).Should(Equal("MaxConns"))
+		Expect(MatchCaseInsensitive("MaxConns")).Should(Equal("maxconns"))
+		Expect(MatchSnakeCase("MaxConns")).Should(Equal("max_conns"))
+		Expect(MatchSnakeCase("max_conns")).Should(Equal("max_conns"))
+		Expect(MatchKebabCase("HTTPServer")).Should(Equal("http-server"))
+		Expect(MatchCamelCase("HTTPServer")).Should(Equal("httpServer"))
+		Expect(MatchCamelCase("http_server")).Should(Equal("httpServer"))
+		Expect(MatchCamelCase("max_über")).Should(Equal("maxÜber"))
+	})
+
+	type conf struct {
+		MaxConns   int
+		HTTPServer string
+		Port       int    `json:"listen_port"`
+		Secret     string `yaml:"-"`
+	}
+
+	Specify("with mixed case keys", func() {
+		x := map[string]interface{}{"MaxConns": 1, "httpserver": "h"}
+		var y conf
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.MaxConns).Should(Equal(1))
+		Expect(y.HTTPServer).Should(Equal("h"))
+	})
+	Specify("exact key takes precedence", func() {
+		x := map[string]interface{}{"maxconns": 1, "MaxConns": 2}
+		var y conf
+		var meta Metadata
+		Expect(New(x).ConvToWithOptions(&y, ConvOptions{Metadata: &meta})).Should(BeNil())
+		Expect(y.MaxConns).Should(Equal(2))
+		Expect(meta.Unused).Should(Equal([]string{"maxconns"}))
+	})
+	Specify("with snake, kebab and camel case", func() {
+		for mapper, x := range map[string]map[string]interface{}{
+			"snake": {"max_conns": 1, "http_server": "h"},
+			"kebab": {"max-conns": 1, "http-server": "h"},
+			"camel": {"maxConns": 1, "httpServer": "h"},
+		} {
+			opts := ConvOptions{FieldNameMapper: map[string]FieldNameMapper{
+				"snake": MatchSnakeCase,
+				"kebab": MatchKebabCase,
+				"camel": MatchCamelCase,
+			}[mapper]}
+			var y conf
+			Expect(New(x).ConvToWithOptions(&y, opts)).Should(BeNil(), mapper)
+			Expect(y.MaxConns).Should(Equal(1), mapper)
+			Expect(y.HTTPServer).Should(Equal("h"), mapper)
+		}
+	})
+	Specify("with exact and custom", func() {
+		var y conf
+		opts := ConvOptions{FieldNameMapper: MatchExact, ErrorUnused: true}
+		Expect(New(map[string]interface{}{"maxconns": 1}).ConvToWithOptions(&y, opts)).
+			Should(BeAssignableToTypeOf((*ErrUnmatched)(nil)))
+
+		opts.FieldNameMapper = func(name string) string {
+			return strings.TrimPrefix(strings.ToLower(name), "app_")
+		}
+		Expect(New(map[string]interface{}{"APP_MAXCONNS": 1}).ConvToWithOptions(&y, opts)).Should(BeNil())
+		Expect(y.MaxConns).Should(Equal(1))
+	})
+	Specify("fall back to json, yaml and mapstructure tags", func() {
+		x := map[string]interface{}{"listen_port": 80, "port": 81, "secret": "s"}
+		var y conf
+		var meta Metadata
+		Expect(New(x).ConvToWithOptions(&y, ConvOptions{Metadata: &meta})).Should(BeNil())
+		Expect(y.Port).Should(Equal(80))
+		Expect(y.Secret).Should(BeEmpty())
+		Expect(meta.Unused).Should(Equal([]string{"port", "secret"}))
+
+		var z struct {
+			A         int `mapstructure:"a_a"`
+			B         int `value:"b" json:"b_b"`
+			FieldBase `yaml:",inline"`
+		}
+		x = map[string]interface{}{"a_a": 1, "b": 2, "id": 3}
+		Expect(New(x).ConvTo(&z)).Should(BeNil())
+		Expect([]int{z.A, z.B, z.ID}).Should(Equal([]int{1, 2, 3}))
+	})
+	Specify("fall back to tags for all options", func() {
+		type fallback struct {
+			Host    string    `json:"host,omitempty"`
+			Port    int       `yaml:"port,required,min=1"`
+			Level   string    `mapstructure:"level,default=info"`
+			Created time.Time `json:"created,layout=2006-01-02"`
+		}
+		var y fallback
+		err := New(map[string]interface{}{"port": 0, "created": "2019-01-11"}).ConvTo(&y)
+		Expect(err.(*ErrMulti).Errs).Should(Equal([]error{
+			&ErrPath{"Value.ConvTo", "port", "port", &ErrInvalid{"Value.ConvTo", "min=1", 0}, nil},
+		}))
+		Expect(y.Level).Should(Equal("info"))
+		Expect(y.Created).Should(Equal(time.Date(2019, 1, 11, 0, 0, 0, 0, time.UTC)))
+
+		err = New(map[string]interface{}{}).ConvTo(&y)
+		Expect(err.(*ErrMulti).Errs).Should(Equal([]error{
+			&ErrPath{"Value.ConvTo", "port", "port", &ErrNotExist{"Value.ConvTo", "port key"}, nil},
+		}))
+
+		Expect(New(fallback{Port: 1}).ToGeneric()).Should(Equal(map[string]interface{}{
+			"port": 1, "level": "", "created": time.Time{},
+		}))
+	})
+})
@@ -0,0 +1,472 @@
+package value
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+)
+
+// SliceStrategy is the strategy of merging slices.
+type SliceStrategy int
+
+const (
+	// SliceReplace replaces the dst slice with a copy of the src one.
+	SliceReplace SliceStrategy = iota
+
+	// SliceAppend appends the src elements to the dst slice.
+	SliceAppend
+
+	// SliceMergeByIndex merges the elements at the same index, and appends the rest of src.
+	SliceMergeByIndex
+
+	// SliceMergeByKey merges the elements that have the same value of MergeOptions.SliceKey,
+	// the map key or struct field, and appends the others of src.
+	SliceMergeByKey
+)
+
+// MergeOptions is the options of MergeWithOptions.
+type MergeOptions struct {
+	// Slice is the strategy of merging slices, it defaults to SliceReplace.
+	// The arrays are always merged by index.
+	Slice SliceStrategy
+
+	// SliceKey is the key of elements for SliceMergeByKey.
+	SliceKey string
+
+	// OverrideNil makes the nil src values override the dst ones, they are skipped by default.
+	OverrideNil bool
+
+	// SkipZero skips the zero src values like 0, "" and false, they override the dst ones by default.
+	SkipZero bool
+
+	// WeaklyTyped and FieldNameMapper are used when the src value is converted
+	// to the type of dst and matched to the struct fields, same as ConvOptions.
+	WeaklyTyped     bool
+	FieldNameMapper FieldNameMapper
+}
+
+// Merge merges srcs into dst in turn, so that the later one takes precedence.
+//
+// The maps and structs are merged recursively by keys, a struct field is matched
+// with its key as Value.ConvTo, the pointers are followed and allocated if nil.
+// The slices are replaced, and the other values override the dst ones
+// with the conversion of Value.ConvTo if the types are different.
+// The src values are copied deeply, so that merging never modifies srcs.
+// It returns ErrPath with the failed path if a src value can't be converted.
+func Merge(dst *Value, srcs ...*Value) error {
+	return MergeWithOptions(dst, srcs, MergeOptions{})
+}
+
+// MergeWithOptions same as Merge with options.
+func MergeWithOptions(dst *Value, srcs []*Value, opts MergeOptions) error {
+	if opts.FieldNameMapper == nil {
+		opts.FieldNameMapper = MatchCaseInsensitive
+	}
+	m := &merger{method: "value.Merge", opts: opts, visiting: map[visit]bool{}}
+
+	rv := dst.getrv()
+	for _, src := range srcs {
+		if src == nil {
+			continue
+		}
+		nv, err := m.merge(rv, src.getrv())
+		if err != nil {
+			return err
+		}
+		rv = nv
+	}
+
+	dst.rv = rv
+	dst.iv = nil
+	return nil
+}
+
+type merger struct {
+	method   string
+	opts     MergeOptions
+	path     []pathSeg
+	visiting map[visit]bool
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func (m *merger) push(key string, index bool) {
+	m.path = append(m.path, pathSeg{key: key, index: index})
+}
+
+func (m *merger) pop() {
+	m.path = m.path[:len(m.path)-1]
+}
+
+// fail returns err with current path.
+func (m *merger) fail(err error) error {
+	var seg string
+	if len(m.path) > 0 {
+		seg = m.path[len(m.path)-1].String()
+	}
+	return &ErrPath{m.method, formatPath(m.path), seg, err, nil}
+}
+
+// enter marks the map or pointer rv as visiting, it returns the func to leave,
+// or ErrCyclic if rv is visiting.
+func (m *merger) enter(rv reflect.Value) (func(), error) {
+	if rv.Kind() != reflect.Map && rv.Kind() != reflect.Ptr {
+		return func() {}, nil
+	}
+	key := visit{rv.Type(), rv.Pointer()}
+	if m.visiting[key] {
+		return nil, m.fail(&ErrCyclic{m.method})
+	}
+	m.visiting[key] = true
+	return func() { delete(m.visiting, key) }, nil
+}
+
+// merge merges src into dst, and returns the new value of dst,
+// the caller must set it back if dst is in a container.
+// It returns dst itself if src is skipped.
+func (m *merger) merge(dst, src reflect.Value) (reflect.Value, error) {
+	for src.Kind() == reflect.Interface && !src.IsNil() {
+		src = src.Elem()
+	}
+	if isNil(src) {
+		if !m.opts.OverrideNil {
+			return dst, nil
+		}
+		if dst.IsValid() {
+			return reflect.Zero(dst.Type()), nil
+		}
+		return src, nil
+	}
+	if m.opts.SkipZero && isZero(src) {
+		return dst, nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Invalid:
+		return m.copy(src), nil
+
+	case reflect.Interface:
+		if dst.IsNil() {
+			return m.copy(src), nil
+		}
+		return m.merge(dst.Elem(), src)
+
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst = reflect.New(dst.Type().Elem())
+		}
+		elem := dst.Elem()
+		nv, err := m.merge(elem, src)
+		if err != nil {
+			return dst, err
+		}
+		if nv, err = m.assign(nv, elem.Type()); err != nil {
+			return dst, err
+		}
+		elem.Set(nv)
+		return dst, nil
+	}
+
+	if src.Kind() == reflect.Ptr {
+		leave, err := m.enter(src)
+		if err != nil {
+			return dst, err
+		}
+		defer leave()
+		return m.merge(dst, src.Elem())
+	}
+	if !isComposite(dst) || !isComposite(src) {
+		return m.copy(src), nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Map, reflect.Struct:
+		if src.Kind() != reflect.Map && src.Kind() != reflect.Struct {
+			return m.copy(src), nil
+		}
+		leave, err := m.enter(src)
+		if err != nil {
+			return dst, err
+		}
+		defer leave()
+		if dst.Kind() == reflect.Map {
+			return m.mergeMap(dst, src)
+		}
+		return m.mergeStruct(dst, src)
+
+	default: // slice or array
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			return m.copy(src), nil
+		}
+		if dst.Kind() == reflect.Array {
+			return m.mergeArray(dst, src)
+		}
+		return m.mergeSlice(dst, src)
+	}
+}
+
+func (m *merger) mergeMap(dst, src reflect.Value) (reflect.Value, error) {
+	if dst.IsNil() {
+		dst = reflect.MakeMap(dst.Type())
+	}
+	entries, err := m.entries(src)
+	if err != nil {
+		return dst, err
+	}
+	for _, e := range entries {
+		m.push(e.name, false)
+		key, err := m.assign(e.key, dst.Type().Key())
+		if err != nil {
+			return dst, err
+		}
+		child := dst.MapIndex(key)
+		nv, err := m.merge(child, e.val)
+		if err != nil {
+			return dst, err
+		}
+		if !nv.IsValid() && !child.IsValid() && !(m.opts.OverrideNil && isNil(e.val)) {
+			m.pop()
+			continue // skipped
+		}
+		if nv, err = m.assign(nv, dst.Type().Elem()); err != nil {
+			return dst, err
+		}
+		dst.SetMapIndex(key, nv)
+		m.pop()
+	}
+	return dst, nil
+}
+
+func (m *merger) mergeStruct(dst, src reflect.Value) (reflect.Value, error) {
+	entries, err := m.entries(src)
+	if err != nil {
+		return dst, err
+	}
+	exact := make(map[string]reflect.Value, len(entries))
+	mapped := make(map[string]reflect.Value, len(entries))
+	for _, e := range entries {
+		exact[e.name] = e.val
+		if _, ok := mapped[m.opts.FieldNameMapper(e.name)]; !ok {
+			mapped[m.opts.FieldNameMapper(e.name)] = e.val
+		}
+	}
+
+	dst = settable(dst)
+	for _, f := range structFields(dst.Type()) {
+		if f.passed || f.remain {
+			continue
+		}
+		val, ok := exact[f.key]
+		if !ok && !f.tagged {
+			val, ok = mapped[m.opts.FieldNameMapper(f.key)]
+		}
+		if !ok {
+			continue
+		}
+
+		m.push(f.key, false)
+		fv := fieldByIndex(dst, f.index, true)
+		nv, err := m.merge(fv, val)
+		if err != nil {
+			return dst, err
+		}
+		if nv, err = m.assign(nv, fv.Type()); err != nil {
+			return dst, err
+		}
+		fv.Set(nv)
+		m.pop()
+	}
+	return dst, nil
+}
+
+func (m *merger) mergeSlice(dst, src reflect.Value) (reflect.Value, error) {
+	et := dst.Type().Elem()
+	switch m.opts.Slice {
+	case SliceAppend:
+		return m.appendCopy(dst, src, 0, nil)
+
+	case SliceMergeByIndex:
+		n := src.Len()
+		if dst.Len() < n {
+			n = dst.Len()
+		}
+		for i := 0; i < n; i++ {
+			if err := m.mergeElem(dst.Index(i), src.Index(i), i); err != nil {
+				return dst, err
+			}
+		}
+		return m.appendCopy(dst, src, n, nil)
+
+	case SliceMergeByKey:
+		idx := map[string]int{}
+		for i := 0; i < dst.Len(); i++ {
+			if key, ok := m.elemKey(dst.Index(i)); ok {
+				if _, dup := idx[key]; !dup {
+					idx[key] = i
+				}
+			}
+		}
+		var rest []int
+		for j := 0; j < src.Len(); j++ {
+			key, ok := m.elemKey(src.Index(j))
+			i, found := idx[key]
+			if !ok || !found {
+				rest = append(rest, j)
+				continue
+			}
+			if err := m.mergeElem(dst.Index(i), src.Index(j), i); err != nil {
+				return dst, err
+			}
+		}
+		return m.appendCopy(dst, src, 0, rest)
+
+	default: // SliceReplace
+		return m.appendCopy(reflect.MakeSlice(reflect.SliceOf(et), 0, src.Len()), src, 0, nil)
+	}
+}
+
+// mergeArray merges the elements of src into the array dst by index.
+func (m *merger) mergeArray(dst, src reflect.Value) (reflect.Value, error) {
+	dst = settable(dst)
+	for i := 0; i < src.Len() && i < dst.Len(); i++ {
+		if err := m.mergeElem(dst.Index(i), src.Index(i), i); err != nil {
+			return dst, err
+		}
+	}
+	return dst, nil
+}
+
+// mergeElem merges src into the settable elem at index i.
+func (m *merger) mergeElem(elem, src reflect.Value, i int) error {
+	m.push(strconv.Itoa(i), true)
+	nv, err := m.merge(elem, src)
+	if err != nil {
+		return err
+	}
+	if nv, err = m.assign(nv, elem.Type()); err != nil {
+		return err
+	}
+	elem.Set(nv)
+	m.pop()
+	return nil
+}
+
+// appendCopy appends the src elements from index start to dst,
+// or the elements at indexes if it's not nil, they are merged into the zero values.
+func (m *merger) appendCopy(dst, src reflect.Value, start int, indexes []int) (reflect.Value, error) {
+	if indexes == nil {
+		for j := start; j < src.Len(); j++ {
+			indexes = append(indexes, j)
+		}
+	}
+	for _, j := range indexes {
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		if err := m.mergeElem(elem, src.Index(j), dst.Len()); err != nil {
+			return dst, err
+		}
+		dst = reflect.Append(dst, elem)
+	}
+	return dst, nil
+}
+
+// elemKey returns the value of MergeOptions.SliceKey of the map or struct elem as string,
+// the key is matched with FieldNameMapper if no exact one.
+func (m *merger) elemKey(elem reflect.Value) (string, bool) {
+	if m.opts.SliceKey == "" {
+		return "", false
+	}
+	elem = indirect(elem)
+	if elem.Kind() != reflect.Map && elem.Kind() != reflect.Struct {
+		return "", false
+	}
+	entries, err := m.entries(elem)
+	if err != nil {
+		return "", false
+	}
+	var val reflect.Value
+	for _, e := range entries {
+		if e.name == m.opts.SliceKey {
+			val = e.val
+			break
+		}
+		if !val.IsValid() && m.opts.FieldNameMapper(e.name) == m.opts.FieldNameMapper(m.opts.SliceKey) {
+			val = e.val
+		}
+	}
+	if !val.IsValid() {
+		return "", false
+	}
+	key, err := (&Value{rv: val}).String()
+	return key, err == nil
+}
+
+// entries returns the entries of map or struct src, the error is with current path.
+func (m *merger) entries(src reflect.Value) ([]entry, error) {
+	entries, err := entriesOf(src)
+	if err != nil {
+		return nil, m.fail(err)
+	}
+	return entries, nil
+}
+
+// copy returns a deep copy of src by Clone, the interface of src is unwrapped.
+func (m *merger) copy(src reflect.Value) reflect.Value {
+	for src.Kind() == reflect.Interface && !src.IsNil() {
+		src = src.Elem()
+	}
+	return newCloner(CloneOptions{}).clone(src)
+}
+
+// assign returns rv as type t, the error is with current path.
+func (m *merger) assign(rv reflect.Value, t reflect.Type) (reflect.Value, error) {
+	opts := ConvOptions{WeaklyTyped: m.opts.WeaklyTyped, FieldNameMapper: m.opts.FieldNameMapper}
+	nv, err := convertTo(rv, t, opts)
+	if err != nil {
+		return rv, m.fail(err)
+	}
+	return nv, nil
+}
+
+// convertTo returns rv as type t, it's converted with Value.ConvTo if it's not assignable.
+func convertTo(rv reflect.Value, t reflect.Type, opts ConvOptions) (reflect.Value, error) {
+	if !rv.IsValid() {
+		return reflect.Zero(t), nil
+	}
+	if rv.Type().AssignableTo(t) {
+		return rv, nil
+	}
+	if isNil(rv) {
+		return reflect.Zero(t), nil
+	}
+
+	p := reflect.New(t)
+	if err := New(rv.Interface()).ConvToWithOptions(p.Interface(), opts); err != nil {
+		return rv, err
+	}
+	return p.Elem(), nil
+}
+
+// isNil reports whether rv is invalid, or a nil interface, pointer, map or slice.
+func isNil(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// isComposite reports whether rv is merged recursively, that is a map, struct, slice or array,
+// except []byte, and the struct implements encoding.TextUnmarshaler like time.Time.
+func isComposite(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		return rv.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Struct:
+		return !reflect.PtrTo(rv.Type()).Implements(textUnmarshalerType) && len(structFields(rv.Type())) > 0
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package value
+
+import (
+	"math"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merge", func() {
+	type server struct {
+		Name string
+		Port int
+	}
+	type conf struct {
+		Host    string
+		Timeout time.Duration
+		Debug   *bool
+		Servers []server
+		Labels  map[string]string
+	}
+
+	Specify("maps in layers", func() {
+		defaults := map[string]interface{}{
+			"db":   map[string]interface{}{"host": "localhost", "port": 5432},
+			"tags": []interface{}{"a"},
+		}
+		file := map[string]interface{}{"db": map[string]interface{}{"port": 6432}, "tags": []interface{}{"b"}}
+		env := map[string]interface{}{"db": map[string]interface{}{"host": "x", "user": nil}}
+
+		v := New(nil)
+		Expect(Merge(v, New(defaults), New(file), New(env))).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{
+			"db":   map[string]interface{}{"host": "x", "port": 6432},
+			"tags": []interface{}{"b"},
+		}))
+
+		// srcs are not modified
+		Expect(defaults["db"]).Should(Equal(map[string]interface{}{"host": "localhost", "port": 5432}))
+		Expect(file["db"]).Should(Equal(map[string]interface{}{"port": 6432}))
+	})
+	Specify("structs and pointers", func() {
+		t := true
+		y := &conf{Host: "localhost", Timeout: time.Second, Labels: map[string]string{"a": "1"}}
+		src := map[string]interface{}{
+			"host":    "x",
+			"timeout": "30s",
+			"debug":   "on",
+			"labels":  map[string]interface{}{"b": 2},
+		}
+		opts := MergeOptions{WeaklyTyped: true}
+		Expect(MergeWithOptions(New(y), []*Value{New(src)}, opts)).Should(BeNil())
+		Expect(y.Host).Should(Equal("x"))
+		Expect(y.Timeout).Should(Equal(30 * time.Second))
+		Expect(y.Debug).Should(Equal(&t))
+		Expect(y.Labels).Should(Equal(map[string]string{"a": "1", "b": "2"}))
+
+		v := New(map[string]interface{}{"host": "h", "port": 1})
+		Expect(Merge(v, New(server{Name: "s"}), New(&server{Port: 2}))).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{"host": "h", "port": 1, "Name": "", "Port": 2}))
+
+		v = New(server{Name: "s", Port: 1})
+		Expect(Merge(v, New(map[string]interface{}{"port": 2}))).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(server{Name: "s", Port: 2}))
+	})
+	Specify("nil and zero values", func() {
+		dst := func() *Value {
+			return New(map[string]interface{}{"a": 1, "b": "b", "c": true})
+		}
+		src := New(map[string]interface{}{"a": nil, "b": "", "d": nil})
+
+		v := dst()
+		Expect(Merge(v, src)).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{"a": 1, "b": "", "c": true}))
+
+		v = dst()
+		Expect(MergeWithOptions(v, []*Value{src}, MergeOptions{OverrideNil: true})).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{"a": nil, "b": "", "c": true, "d": nil}))
+
+		v = dst()
+		Expect(MergeWithOptions(v, []*Value{src}, MergeOptions{SkipZero: true})).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{"a": 1, "b": "b", "c": true}))
+	})
+	Specify("slice strategies", func() {
+		dst := func() *conf {
+			return &conf{Servers: []server{{"a", 1}, {"b", 2}}}
+		}
+		src := New(map[string]interface{}{"servers": []interface{}{
+			map[string]interface{}{"name": "b", "port": 20},
+			map[string]interface{}{"name": "c"},
+		}})
+
+		for strategy, servers := range map[SliceStrategy][]server{
+			SliceReplace:      {{"b", 20}, {"c", 0}},
+			SliceAppend:       {{"a", 1}, {"b", 2}, {"b", 20}, {"c", 0}},
+			SliceMergeByIndex: {{"b", 20}, {"c", 2}},
+			SliceMergeByKey:   {{"a", 1}, {"b", 20}, {"c", 0}},
+		} {
+			y := dst()
+			opts := MergeOptions{Slice: strategy, SliceKey: "Name"}
+			Expect(MergeWithOptions(New(y), []*Value{src}, opts)).Should(BeNil(), "strategy %d", strategy)
+			Expect(y.Servers).Should(Equal(servers), "strategy %d", strategy)
+		}
+
+		v := New(map[string]interface{}{"a": [2]int{1, 2}})
+		Expect(Merge(v, New(map[string]interface{}{"a": []int{3}}))).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(map[string]interface{}{"a": [2]int{3, 2}}))
+	})
+	Specify("errors", func() {
+		y := &conf{}
+		err := Merge(New(y), New(map[string]interface{}{"servers": []interface{}{map[string]interface{}{"port": "x"}}}))
+		Expect(err).Should(BeAssignableToTypeOf((*ErrPath)(nil)))
+		Expect(err.(*ErrPath).Path).Should(Equal("Servers[0].Port"))
+
+		m1 := map[string]interface{}{}
+		m1["m"] = m1
+		m2 := map[string]interface{}{}
+		m2["m"] = m2
+		Expect(Merge(New(m1), New(m2))).Should(BeAssignableToTypeOf((*ErrPath)(nil)))
+	})
+	Specify("cyclic source", func() {
+		m := map[string]interface{}{}
+		m["m"] = m
+		v := New(nil)
+		Expect(Merge(v, New(m))).Should(BeNil())
+		cp := v.Interface().(map[string]interface{})
+		Expect(reflect.ValueOf(cp["m"]).Pointer()).Should(Equal(reflect.ValueOf(cp).Pointer()))
+		Expect(reflect.ValueOf(cp).Pointer()).ShouldNot(Equal(reflect.ValueOf(m).Pointer()))
+	})
+	Specify("isZero()", func() {
+		var ip *int
+		for _, x := range []interface{}{
+			0, uint8(0), 0.0, complex64(0), "", false, ip, []int(nil), map[string]int(nil),
+			[2]int{}, struct{ A int }{}, time.Time{},
+		} {
+			Expect(isZero(reflect.ValueOf(x))).Should(BeTrue(), "%#v", x)
+		}
+		for _, x := range []interface{}{
+			1, uint8(1), math.Copysign(0, -1), complex64(1i), "a", true, new(int), []int{}, map[string]int{},
+			[2]int{0, 1}, struct{ A int }{1}, time.Now(),
+		} {
+			Expect(isZero(reflect.ValueOf(x))).Should(BeFalse(), "%#v", x)
+		}
+	})
+})
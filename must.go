@@ -162,6 +162,15 @@ func (v *Value) MustGet(k interface{}) *Value {
 	return val
 }
 
+// MustGetPath must api for GetPath
+func (v *Value) MustGetPath(path string) *Value {
+	val, err := v.GetPath(path)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
 // MustMap must api for Map
 func (v *Value) MustMap() map[*Value]*Value {
 	tm, err := v.Map()
@@ -0,0 +1,187 @@
+package value
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConvOptions is the options of Value.ConvToWithOptions.
+type ConvOptions struct {
+	// ErrorUnused reports the source keys that match no struct field.
+	ErrorUnused bool
+
+	// ErrorUnset reports the struct fields that no source key matches.
+	ErrorUnset bool
+
+	// WeaklyTyped makes the conversion between string, number and bool lenient.
+	// The bool accepts yes/no, on/off and 1/0, and the number accepts base prefixes
+	// like 0x, 0o, 0b and underscores. A single scalar is converted to a one-element slice,
+	// and an empty string is converted to the zero value of any non-string type.
+	WeaklyTyped bool
+
+	// FieldNameMapper matches the source keys to the struct fields which have no tag name,
+	// it defaults to MatchCaseInsensitive.
+	FieldNameMapper FieldNameMapper
+
+	// TimeLayouts are the layouts tried in order when converting string to time.Time,
+	// it defaults to TimeLayout and TimeLayouts. It's overridden by tag option `layout=...`.
+	TimeLayouts []string
+
+	// EpochUnit is the unit of number converting to time.Time since Unix epoch,
+	// it defaults to time.Second. It's overridden by tag option like `epoch=ms`.
+	EpochUnit time.Duration
+
+	// Converter is consulted before the kind-based conversion,
+	// it defaults to the global registrations.
+	Converter *Converter
+
+	// Metadata is filled with the result of conversion if it's not nil.
+	Metadata *Metadata
+}
+
+// Metadata is the result of conversion,
+// the keys are full paths like "servers[0].timeout" and sorted.
+type Metadata struct {
+	// Keys are the decoded keys.
+	Keys []string
+
+	// Unused are the source keys that match no struct field.
+	Unused []string
+
+	// Unset are the struct fields that no source key matches, and have no default value.
+	Unset []string
+
+	// Defaulted are the struct fields that set with the default value.
+	Defaulted []string
+}
+
+// convState is the state of a conversion.
+type convState struct {
+	opts ConvOptions
+	conv *Converter
+	src  *Value // root source for positions
+
+	timeFmt  timeFormat
+	timeBase timeFormat // of options, which the struct fields start with
+
+	path   []pathSeg
+	errs   []error
+	keys   []string
+	unused []string
+	unset  []string
+
+	defaulted  []string
+	defaulting bool // converting a default value, whose keys are not decoded
+	parsed     bool // converting the JSON parsed from string, whose bytes are base64
+}
+
+func newConvState(opts ConvOptions) *convState {
+	s := &convState{opts: opts, conv: opts.Converter}
+	if s.conv == nil {
+		s.conv = defaultConverter
+	}
+	s.timeFmt = timeFormat{layouts: opts.TimeLayouts, epoch: opts.EpochUnit}
+	if s.timeFmt.epoch <= 0 {
+		s.timeFmt.epoch = time.Second
+	}
+	s.timeBase = s.timeFmt
+	return s
+}
+
+// push enters the key, the index indicates key is an index of array/slice.
+func (s *convState) push(key string, index bool) {
+	s.path = append(s.path, pathSeg{key: key, index: index})
+}
+
+func (s *convState) pop() {
+	s.path = s.path[:len(s.path)-1]
+}
+
+// pathOf returns the path of key in current path.
+func (s *convState) pathOf(key string) string {
+	return formatPath(append(s.path[:len(s.path):len(s.path)], pathSeg{key: key}))
+}
+
+// decoded records current path as a decoded key.
+func (s *convState) decoded() {
+	if s.opts.Metadata != nil && !s.defaulting {
+		s.keys = append(s.keys, formatPath(s.path))
+	}
+}
+
+// addDefaulted records current path as a defaulted field.
+func (s *convState) addDefaulted() {
+	if s.opts.Metadata != nil {
+		s.defaulted = append(s.defaulted, formatPath(s.path))
+	}
+}
+
+func (s *convState) addUnused(key string) {
+	if s.opts.ErrorUnused || s.opts.Metadata != nil {
+		s.unused = append(s.unused, s.pathOf(key))
+	}
+}
+
+func (s *convState) addUnset(key string) {
+	if s.opts.ErrorUnset || s.opts.Metadata != nil {
+		s.unset = append(s.unset, s.pathOf(key))
+	}
+}
+
+// fail collects the err of current path.
+func (s *convState) fail(err error) {
+	seg := s.path[len(s.path)-1]
+	s.errs = append(s.errs, &ErrPath{"Value.ConvTo", formatPath(s.path), seg.String(), err, s.src.positionOf(s.path)})
+}
+
+// fillMetadata fills the metadata of options if it's not nil.
+func (s *convState) fillMetadata() {
+	sort.Strings(s.keys)
+	sort.Strings(s.unused)
+	sort.Strings(s.unset)
+	sort.Strings(s.defaulted)
+	if m := s.opts.Metadata; m != nil {
+		m.Keys, m.Unused, m.Unset = s.keys, s.unused, s.unset
+		m.Defaulted = s.defaulted
+	}
+}
+
+// err returns the collected errors, and the error of unused keys and unset fields if any.
+func (s *convState) err() error {
+	var unused, unset []string
+	if s.opts.ErrorUnused {
+		unused = s.unused
+	}
+	if s.opts.ErrorUnset {
+		unset = s.unset
+	}
+
+	var unmatched error
+	if len(unused) > 0 || len(unset) > 0 {
+		unmatched = &ErrUnmatched{"Value.ConvTo", unused, unset}
+	}
+	if len(s.errs) == 0 {
+		return unmatched
+	}
+
+	sort.SliceStable(s.errs, func(i, j int) bool {
+		return s.errs[i].(*ErrPath).Path < s.errs[j].(*ErrPath).Path
+	})
+	if unmatched != nil {
+		s.errs = append(s.errs, unmatched)
+	}
+	return &ErrMulti{"Value.ConvTo", s.errs}
+}
+
+// formatPath formats segs to path like "a[0].b".
+func formatPath(segs []pathSeg) string {
+	var b strings.Builder
+	for i, seg := range segs {
+		if i > 0 && !seg.index {
+			b.WriteByte('.')
+		}
+		b.WriteString(seg.String())
+	}
+	return b.String()
+}
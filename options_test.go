@@ -0,0 +1,89 @@
+package value
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConvToWithOptions", func() {
+	type server struct {
+		Host    string
+		Port    int `value:"port"`
+		Timeout string
+	}
+	type config struct {
+		Name    string
+		Servers []server
+		Skip    string `value:"-"`
+	}
+	x := map[string]interface{}{
+		"name": "a",
+		"servers": []interface{}{
+			map[string]interface{}{"host": "h", "port": 80, "timout": "1s"},
+		},
+		"extra": true,
+	}
+
+	Specify("without strict options", func() {
+		var y config
+		Expect(New(x).ConvToWithOptions(&y, ConvOptions{})).Should(BeNil())
+		Expect(y.Servers[0].Port).Should(Equal(80))
+	})
+	Specify("with ErrorUnused", func() {
+		var y config
+		err := New(x).ConvToWithOptions(&y, ConvOptions{ErrorUnused: true})
+		Expect(err).Should(Equal(&ErrUnmatched{
+			Method: "Value.ConvTo",
+			Unused: []string{"extra", "servers[0].timout"},
+		}))
+		Expect(y.Servers[0].Port).Should(Equal(80))
+	})
+	Specify("with ErrorUnset", func() {
+		var y config
+		err := New(x).ConvToWithOptions(&y, ConvOptions{ErrorUnset: true})
+		Expect(err).Should(Equal(&ErrUnmatched{
+			Method: "Value.ConvTo",
+			Unset:  []string{"servers[0].Timeout"},
+		}))
+	})
+	Specify("with both", func() {
+		var y config
+		err := New(x).ConvToWithOptions(&y, ConvOptions{ErrorUnused: true, ErrorUnset: true})
+		Expect(err).To(BeAssignableToTypeOf((*ErrUnmatched)(nil)))
+		Expect(err.Error()).Should(Equal("table: call of Value.ConvTo with unused keys extra, servers[0].timout and unset fields servers[0].Timeout"))
+	})
+	Specify("with conversion errors", func() {
+		var y config
+		err := New(map[string]interface{}{
+			"servers": []interface{}{map[string]interface{}{"port": "x"}},
+			"extra":   1,
+		}).ConvToWithOptions(&y, ConvOptions{ErrorUnused: true})
+		Expect(err).To(BeAssignableToTypeOf((*ErrMulti)(nil)))
+
+		errs := err.(*ErrMulti).Errs
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].(*ErrPath).Path).To(Equal("servers[0].port"))
+		Expect(errs[1]).To(BeAssignableToTypeOf((*ErrUnmatched)(nil)))
+	})
+	Specify("with Metadata", func() {
+		var y config
+		var meta Metadata
+		err := New(x).ConvToWithOptions(&y, ConvOptions{Metadata: &meta})
+		Expect(err).Should(BeNil())
+		Expect(meta).Should(Equal(Metadata{
+			Keys:   []string{"name", "servers", "servers[0]", "servers[0].host", "servers[0].port"},
+			Unused: []string{"extra", "servers[0].timout"},
+			Unset:  []string{"servers[0].Timeout"},
+		}))
+	})
+	Specify("with Metadata and ErrorUnused", func() {
+		var y config
+		var meta Metadata
+		err := New(x).ConvToWithOptions(&y, ConvOptions{ErrorUnused: true, Metadata: &meta})
+		Expect(err).Should(Equal(&ErrUnmatched{
+			Method: "Value.ConvTo",
+			Unused: []string{"extra", "servers[0].timout"},
+		}))
+		Expect(meta.Unset).Should(Equal([]string{"servers[0].Timeout"}))
+	})
+})
@@ -0,0 +1,558 @@
+package value
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchOp is an operation of JSON Patch (RFC 6902),
+// the Path and From are JSON Pointers (RFC 6901) like "/servers/0/port".
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, the value is formatted as Value.String,
+// and it's omitted unless the op is add, replace or test.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"op": op.Op, "path": op.Path}
+	if op.From != "" {
+		m["from"] = op.From
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		m["value"] = New(op.Value)
+	}
+	return json.Marshal(m)
+}
+
+// Patch is a JSON Patch document.
+type Patch []PatchOp
+
+// ParsePatch parses the JSON Patch document data.
+func ParsePatch(data []byte) (Patch, error) {
+	var patch Patch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	return patch, nil
+}
+
+// NewPatch returns the patch of changes, such that applying it to a results in b
+// if changes is Diff(a, b). The removes are in the reverse order,
+// so that the indexes of slices are not shifted by the previous ones.
+func NewPatch(changes []Change) Patch {
+	var patch, removes Patch
+	for _, c := range changes {
+		switch c.Type {
+		case ChangeAdded:
+			patch = append(patch, PatchOp{Op: "add", Path: c.Pointer(), Value: c.To})
+		case ChangeRemoved:
+			removes = append(removes, PatchOp{Op: "remove", Path: c.Pointer()})
+		default:
+			patch = append(patch, PatchOp{Op: "replace", Path: c.Pointer(), Value: c.To})
+		}
+	}
+	for i := len(removes) - 1; i >= 0; i-- {
+		patch = append(patch, removes[i])
+	}
+	return patch
+}
+
+// ApplyPatch applies the JSON Patch to t, the ops are add, remove, replace, move, copy and test.
+//
+// It works on maps, slices, arrays, structs and the pointers to them, the struct fields
+// are matched with their keys as Value.ConvTo, and the values are converted to the types
+// of targets with Value.ConvTo. A field or an element of array can't be removed,
+// so that the remove of them sets the zero value, and the add of them replaces.
+// The patch is applied to a copy of t, and then set back if all ops succeed,
+// the root pointer or map is modified in place.
+// It returns ErrPath with the path of failed op, which wraps ErrInvalid if a test fails.
+func (v *Value) ApplyPatch(patch Patch) error {
+	return v.applyCopy("Value.ApplyPatch", func(p *patcher, root reflect.Value) (reflect.Value, error) {
+		for _, op := range patch {
+			nv, err := p.apply(root, op)
+			if err != nil {
+				return root, &ErrPath{p.method, op.Path, "", err, nil}
+			}
+			root = nv
+		}
+		return root, nil
+	})
+}
+
+// ApplyMergePatch applies the JSON Merge Patch (RFC 7396) to t,
+// the maps and structs of patch are merged recursively, a null removes the key
+// or sets the zero value of field, and the other values replace the ones of t.
+// It works on the values as ApplyPatch.
+func (v *Value) ApplyMergePatch(patch *Value) error {
+	return v.applyCopy("Value.ApplyMergePatch", func(p *patcher, root reflect.Value) (reflect.Value, error) {
+		return p.mergePatch(root, patch.getrv())
+	})
+}
+
+type patcher struct {
+	method string
+	m      *merger // copies values and tracks the path of merge patch
+}
+
+// applyCopy applies f to a copy of t, and sets it back if succeeded.
+func (v *Value) applyCopy(method string, f func(p *patcher, root reflect.Value) (reflect.Value, error)) error {
+	p := &patcher{method: method, m: &merger{method: method, visiting: map[visit]bool{}}}
+	p.m.opts.FieldNameMapper = MatchCaseInsensitive
+
+	orig := v.getrv()
+	root, err := f(p, p.m.copy(orig))
+	if err != nil {
+		return err
+	}
+
+	if root.IsValid() && orig.IsValid() && root.Type() == orig.Type() && !isNil(root) && !isNil(orig) {
+		switch orig.Kind() {
+		case reflect.Ptr:
+			orig.Elem().Set(root.Elem())
+			root = orig
+		case reflect.Map:
+			for _, key := range orig.MapKeys() {
+				if !root.MapIndex(key).IsValid() {
+					orig.SetMapIndex(key, reflect.Value{})
+				}
+			}
+			iter := root.MapRange()
+			for iter.Next() {
+				orig.SetMapIndex(iter.Key(), iter.Value())
+			}
+			root = orig
+		}
+	}
+
+	v.rv = root
+	v.iv = nil
+	return nil
+}
+
+func (p *patcher) apply(root reflect.Value, op PatchOp) (reflect.Value, error) {
+	tokens, err := p.parsePointer(op.Path)
+	if err != nil {
+		return root, err
+	}
+
+	switch op.Op {
+	case "add", "replace":
+		val := p.m.copy(reflect.ValueOf(op.Value))
+		if op.Op == "add" {
+			return p.add(root, tokens, val)
+		}
+		if _, err := p.get(root, tokens); err != nil {
+			return root, err
+		}
+		if len(tokens) == 0 {
+			return p.replaceRoot(root, val)
+		}
+		return p.update(root, tokens, func(parent reflect.Value, token string) (reflect.Value, error) {
+			return p.set(parent, token, val)
+		})
+
+	case "remove":
+		return p.remove(root, tokens)
+
+	case "move", "copy":
+		from, err := p.parsePointer(op.From)
+		if err != nil {
+			return root, err
+		}
+		if op.Op == "move" && strings.HasPrefix(op.Path, op.From+"/") {
+			return root, &ErrInvalid{p.method, "move to child", op.From}
+		}
+		val, err := p.get(root, from)
+		if err != nil {
+			return root, err
+		}
+		val = p.m.copy(val)
+		if op.Op == "move" {
+			if root, err = p.remove(root, from); err != nil {
+				return root, err
+			}
+		}
+		return p.add(root, tokens, val)
+
+	case "test":
+		val, err := p.get(root, tokens)
+		if err != nil {
+			return root, err
+		}
+		if !(&Value{rv: val}).EqualWithOptions(New(op.Value), CompareOptions{IgnoreNumericKind: true}) {
+			return root, &ErrInvalid{p.method, "test", op.Value}
+		}
+		return root, nil
+
+	default:
+		return root, &ErrNotExist{p.method, "op " + op.Op}
+	}
+}
+
+// parsePointer parses the JSON Pointer s to tokens.
+func (p *patcher) parsePointer(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if s[0] != '/' {
+		return nil, &ErrInvalid{p.method, "JSON pointer", s}
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// formatPointer formats segs to JSON Pointer like "/a/0/b".
+func formatPointer(segs []pathSeg) string {
+	var b strings.Builder
+	for _, seg := range segs {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(seg.key))
+	}
+	return b.String()
+}
+
+// get returns the value of root at tokens.
+func (p *patcher) get(root reflect.Value, tokens []string) (reflect.Value, error) {
+	cur := root
+	for _, token := range tokens {
+		val, ok, err := p.lookup(derefNonNil(cur), token)
+		if err != nil {
+			return cur, err
+		}
+		if !ok {
+			return cur, &ErrNotExist{p.method, token + " key"}
+		}
+		cur = val
+	}
+	return cur, nil
+}
+
+// add adds val to root at tokens, it's inserted if the parent is a slice,
+// otherwise it's set.
+func (p *patcher) add(root reflect.Value, tokens []string, val reflect.Value) (reflect.Value, error) {
+	if len(tokens) == 0 {
+		return p.replaceRoot(root, val)
+	}
+	return p.update(root, tokens, func(parent reflect.Value, token string) (reflect.Value, error) {
+		if parent.Kind() == reflect.Slice {
+			return p.insert(parent, token, val)
+		}
+		return p.set(parent, token, val)
+	})
+}
+
+// remove removes the value of root at tokens, which must exist.
+func (p *patcher) remove(root reflect.Value, tokens []string) (reflect.Value, error) {
+	if len(tokens) == 0 {
+		return reflect.Value{}, nil
+	}
+	return p.update(root, tokens, func(parent reflect.Value, token string) (reflect.Value, error) {
+		return p.del(parent, token, false)
+	})
+}
+
+// update calls f with the parent of tokens and the last token, and returns the new root,
+// the values on the way are set back if they are changed.
+func (p *patcher) update(cur reflect.Value, tokens []string,
+	f func(parent reflect.Value, token string) (reflect.Value, error)) (reflect.Value, error) {
+	switch cur.Kind() {
+	case reflect.Interface:
+		if !cur.IsNil() {
+			return p.update(cur.Elem(), tokens, f)
+		}
+	case reflect.Ptr:
+		if !cur.IsNil() {
+			elem := cur.Elem()
+			nv, err := p.update(elem, tokens, f)
+			if err != nil {
+				return cur, err
+			}
+			if nv, err = convertTo(nv, elem.Type(), ConvOptions{}); err != nil {
+				return cur, err
+			}
+			elem.Set(nv)
+			return cur, nil
+		}
+	}
+
+	if len(tokens) == 1 {
+		return f(cur, tokens[0])
+	}
+	child, ok, err := p.lookup(cur, tokens[0])
+	if err != nil {
+		return cur, err
+	}
+	if !ok {
+		return cur, &ErrNotExist{p.method, tokens[0] + " key"}
+	}
+	nv, err := p.update(child, tokens[1:], f)
+	if err != nil {
+		return cur, err
+	}
+	return p.set(cur, tokens[0], nv)
+}
+
+// lookup returns the child of cur with token, it's not ok if the map key is missing.
+func (p *patcher) lookup(cur reflect.Value, token string) (reflect.Value, bool, error) {
+	switch cur.Kind() {
+	case reflect.Map:
+		key, err := pathSeg{key: token}.mapKey(cur.Type().Key())
+		if err != nil {
+			return cur, false, err
+		}
+		val := cur.MapIndex(key)
+		return val, val.IsValid(), nil
+
+	case reflect.Slice, reflect.Array:
+		i, err := p.index(token, cur.Len(), false)
+		if err != nil {
+			return cur, false, err
+		}
+		return cur.Index(i), true, nil
+
+	case reflect.Struct:
+		f, err := p.field(cur.Type(), token)
+		if err != nil {
+			return cur, false, err
+		}
+		fv := fieldByIndex(cur, f.index, false)
+		if !fv.IsValid() { // in nil embedded pointer
+			fv = reflect.Zero(f.Type)
+		}
+		return fv, true, nil
+
+	default:
+		return cur, false, &ErrUnsupportedKind{p.method, cur.Kind()}
+	}
+}
+
+// set sets the child of cur with token to val, and returns the new cur.
+func (p *patcher) set(cur reflect.Value, token string, val reflect.Value) (reflect.Value, error) {
+	switch cur.Kind() {
+	case reflect.Map:
+		if cur.IsNil() {
+			cur = reflect.MakeMap(cur.Type())
+		}
+		key, err := pathSeg{key: token}.mapKey(cur.Type().Key())
+		if err != nil {
+			return cur, err
+		}
+		nv, err := convertTo(val, cur.Type().Elem(), ConvOptions{})
+		if err != nil {
+			return cur, err
+		}
+		cur.SetMapIndex(key, nv)
+		return cur, nil
+
+	case reflect.Slice, reflect.Array:
+		i, err := p.index(token, cur.Len(), false)
+		if err != nil {
+			return cur, err
+		}
+		cur = settable(cur)
+		return cur, p.setValue(cur.Index(i), val)
+
+	case reflect.Struct:
+		f, err := p.field(cur.Type(), token)
+		if err != nil {
+			return cur, err
+		}
+		cur = settable(cur)
+		return cur, p.setValue(fieldByIndex(cur, f.index, true), val)
+
+	default:
+		return cur, &ErrUnsupportedKind{p.method, cur.Kind()}
+	}
+}
+
+// insert inserts val to the slice cur at index token, or appends it if token is "-".
+func (p *patcher) insert(cur reflect.Value, token string, val reflect.Value) (reflect.Value, error) {
+	i, err := p.index(token, cur.Len(), true)
+	if err != nil {
+		return cur, err
+	}
+	ev, err := convertTo(val, cur.Type().Elem(), ConvOptions{})
+	if err != nil {
+		return cur, err
+	}
+	ns := reflect.MakeSlice(cur.Type(), 0, cur.Len()+1)
+	ns = reflect.AppendSlice(ns, cur.Slice(0, i))
+	ns = reflect.Append(ns, ev)
+	return reflect.AppendSlice(ns, cur.Slice(i, cur.Len())), nil
+}
+
+// del removes the child of cur with token, the field of struct and the element of array
+// are set to zero value. The missing map key is ignored if missingOK.
+func (p *patcher) del(cur reflect.Value, token string, missingOK bool) (reflect.Value, error) {
+	switch cur.Kind() {
+	case reflect.Map:
+		key, err := pathSeg{key: token}.mapKey(cur.Type().Key())
+		if err != nil {
+			return cur, err
+		}
+		if !cur.MapIndex(key).IsValid() {
+			if missingOK {
+				return cur, nil
+			}
+			return cur, &ErrNotExist{p.method, token + " key"}
+		}
+		cur.SetMapIndex(key, reflect.Value{})
+		return cur, nil
+
+	case reflect.Slice:
+		i, err := p.index(token, cur.Len(), false)
+		if err != nil {
+			return cur, err
+		}
+		ns := reflect.MakeSlice(cur.Type(), 0, cur.Len()-1)
+		ns = reflect.AppendSlice(ns, cur.Slice(0, i))
+		return reflect.AppendSlice(ns, cur.Slice(i+1, cur.Len())), nil
+
+	default:
+		return p.set(cur, token, reflect.Value{})
+	}
+}
+
+// setValue sets val to the settable dst with conversion.
+func (p *patcher) setValue(dst, val reflect.Value) error {
+	if !dst.CanSet() {
+		return &ErrCannotSet{p.method}
+	}
+	nv, err := convertTo(val, dst.Type(), ConvOptions{})
+	if err != nil {
+		return err
+	}
+	dst.Set(nv)
+	return nil
+}
+
+// replaceRoot returns val as the new root, it's converted to the type of root pointer,
+// so that the pointed value is replaced.
+func (p *patcher) replaceRoot(root, val reflect.Value) (reflect.Value, error) {
+	if root.Kind() != reflect.Ptr || root.IsNil() {
+		return val, nil
+	}
+	return convertTo(val, root.Type(), ConvOptions{})
+}
+
+// convert returns val as the type of dst, or val itself if dst is invalid or an interface.
+func (p *patcher) convert(val, dst reflect.Value) (reflect.Value, error) {
+	if !dst.IsValid() || dst.Kind() == reflect.Interface {
+		return val, nil
+	}
+	return convertTo(val, dst.Type(), ConvOptions{})
+}
+
+// index parses token as the index of array with length n,
+// the index can be n or "-" for the end if add.
+func (p *patcher) index(token string, n int, add bool) (int, error) {
+	if add && token == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || token != strconv.Itoa(i) {
+		return 0, &ErrNotExist{p.method, token + " index"}
+	}
+	if i > n || i == n && !add {
+		return 0, &ErrOutOfRange{p.method}
+	}
+	return i, nil
+}
+
+// field returns the field of struct type t with key token,
+// it's matched case-insensitively if the field is not tagged.
+func (p *patcher) field(t reflect.Type, token string) (structField, error) {
+	fields := structFields(t)
+	for _, f := range fields {
+		if !f.passed && !f.remain && f.key == token {
+			return f, nil
+		}
+	}
+	for _, f := range fields {
+		if !f.passed && !f.remain && !f.tagged && MatchCaseInsensitive(f.key) == MatchCaseInsensitive(token) {
+			return f, nil
+		}
+	}
+	return structField{}, &ErrNotExist{p.method, token + " field"}
+}
+
+// mergePatch merges patch into target as JSON Merge Patch, and returns the new target.
+func (p *patcher) mergePatch(target, patch reflect.Value) (reflect.Value, error) {
+	patch = derefNonNil(patch)
+	if keyedKind(patch) != reflect.Map {
+		if isNilRef(patch) {
+			return p.convert(reflect.Value{}, target)
+		}
+		val, err := p.convert(p.m.copy(patch), target)
+		if err != nil {
+			return target, p.m.fail(err)
+		}
+		return val, nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		if !target.IsNil() {
+			return p.mergePatch(target.Elem(), patch)
+		}
+	case reflect.Ptr:
+		if target.IsNil() {
+			target = reflect.New(target.Type().Elem())
+		}
+		elem := target.Elem()
+		nv, err := p.mergePatch(elem, patch)
+		if err != nil {
+			return target, err
+		}
+		if err = p.setValue(elem, nv); err != nil {
+			return target, p.m.fail(err)
+		}
+		return target, nil
+	}
+	if keyedKind(target) != reflect.Map {
+		target = reflect.ValueOf(map[string]interface{}{})
+	}
+
+	entries, err := entriesOf(patch)
+	if err != nil {
+		return target, p.m.fail(err)
+	}
+	for _, e := range entries {
+		p.m.push(e.name, false)
+		if isNilRef(derefNonNil(e.val)) {
+			if target, err = p.del(target, e.name, true); err != nil {
+				return target, p.m.fail(err)
+			}
+			p.m.pop()
+			continue
+		}
+
+		child, _, err := p.lookup(target, e.name)
+		if err != nil {
+			return target, p.m.fail(err)
+		}
+		nv, err := p.mergePatch(child, e.val)
+		if err != nil {
+			return target, err
+		}
+		if target, err = p.set(target, e.name, nv); err != nil {
+			return target, p.m.fail(err)
+		}
+		p.m.pop()
+	}
+	return target, nil
+}
@@ -0,0 +1,174 @@
+package value
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Patch", func() {
+	type tls struct {
+		Cert string `json:"cert"`
+	}
+	type server struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+	type conf struct {
+		Name    string   `json:"name"`
+		Port    int      `json:"port"`
+		TLS     *tls     `json:"tls"`
+		Servers []server `json:"servers"`
+		Tags    [2]string
+	}
+
+	fromJSON := func(s string) interface{} {
+		var x interface{}
+		Expect(json.Unmarshal([]byte(s), &x)).Should(Succeed())
+		return x
+	}
+
+	Specify("ApplyPatch() on generic tree", func() {
+		x := fromJSON(`{"a": {"b": [1, 2]}, "c~d": "e", "f/g": 1}`).(map[string]interface{})
+		patch, err := ParsePatch([]byte(`[
+			{"op": "test", "path": "/a/b/0", "value": 1},
+			{"op": "add", "path": "/a/b/1", "value": {"x": null}},
+			{"op": "add", "path": "/a/b/-", "value": 3},
+			{"op": "remove", "path": "/a/b/0"},
+			{"op": "replace", "path": "/c~0d", "value": ["e"]},
+			{"op": "move", "from": "/f~1g", "path": "/h"},
+			{"op": "copy", "from": "/a/b", "path": "/i"}
+		]`))
+		Expect(err).Should(BeNil())
+
+		v := New(x)
+		Expect(v.ApplyPatch(patch)).Should(BeNil())
+		Expect(x).Should(Equal(fromJSON(`{
+			"a": {"b": [{"x": null}, 2, 3]},
+			"c~d": ["e"],
+			"h": 1,
+			"i": [{"x": null}, 2, 3]
+		}`)))
+
+		// the copy is not shared
+		Expect(v.PutPath("i[1]", 0)).Should(BeNil())
+		Expect(x["a"]).Should(Equal(fromJSON(`{"b": [{"x": null}, 2, 3]}`)))
+
+		Expect(v.ApplyPatch(Patch{{Op: "replace", Path: "", Value: 1}})).Should(BeNil())
+		Expect(v.Interface()).Should(Equal(1))
+	})
+	Specify("ApplyPatch() on struct", func() {
+		y := &conf{Name: "a", Port: 80, Servers: []server{{"s1", 1}}}
+		patch, err := ParsePatch([]byte(`[
+			{"op": "replace", "path": "/port", "value": 81},
+			{"op": "add", "path": "/servers/0", "value": {"name": "s0"}},
+			{"op": "add", "path": "/tls", "value": {"cert": "c"}},
+			{"op": "remove", "path": "/name"},
+			{"op": "add", "path": "/tags/1", "value": "t"}
+		]`))
+		Expect(err).Should(BeNil())
+		Expect(New(y).ApplyPatch(patch)).Should(BeNil())
+		Expect(*y).Should(Equal(conf{
+			Port:    81,
+			TLS:     &tls{"c"},
+			Servers: []server{{"s0", 0}, {"s1", 1}},
+			Tags:    [2]string{"", "t"},
+		}))
+
+		Expect(New(y).ApplyPatch(Patch{{Op: "replace", Path: "", Value: map[string]interface{}{"name": "b"}}})).Should(BeNil())
+		Expect(*y).Should(Equal(conf{Name: "b"}))
+
+		servers := []server{{"a", 1}, {"b", 2}}
+		Expect(New(&servers).ApplyPatch(Patch{{Op: "move", Path: "/0/port", From: "/1/port"}})).Should(BeNil())
+		Expect(servers).Should(Equal([]server{{"a", 2}, {"b", 0}}))
+	})
+	Specify("ApplyPatch() errors", func() {
+		y := &conf{Name: "a", Port: 80}
+		for _, op := range []PatchOp{
+			{Op: "test", Path: "/port", Value: 81},
+			{Op: "remove", Path: "/servers/0"},
+			{Op: "add", Path: "/servers/1", Value: server{}},
+			{Op: "replace", Path: "/none", Value: 1},
+			{Op: "replace", Path: "/port", Value: "x"},
+			{Op: "move", Path: "/tls/cert", From: "/tls"},
+			{Op: "add", Path: "port", Value: 1},
+			{Op: "patch", Path: "/port"},
+		} {
+			err := New(y).ApplyPatch(Patch{{Op: "replace", Path: "/name", Value: "b"}, op})
+			Expect(err).Should(BeAssignableToTypeOf((*ErrPath)(nil)), op.Op)
+			Expect(err.(*ErrPath).Path).Should(Equal(op.Path))
+		}
+		Expect(*y).Should(Equal(conf{Name: "a", Port: 80}))
+
+		err := New(y).ApplyPatch(Patch{{Op: "test", Path: "/port", Value: 81}})
+		Expect(err.(*ErrPath).Err).Should(BeAssignableToTypeOf((*ErrInvalid)(nil)))
+	})
+	Specify("ApplyPatch() on cyclic graph", func() {
+		type node struct {
+			Name string
+			Next *node
+		}
+		n := &node{Name: "a"}
+		n.Next = n
+		Expect(New(n).ApplyPatch(Patch{{Op: "replace", Path: "/Name", Value: "b"}})).Should(BeNil())
+		Expect(n.Name).Should(Equal("b"))
+		Expect(n.Next.Name).Should(Equal("b"))
+		Expect(n.Next.Next).Should(BeIdenticalTo(n.Next))
+
+		m := map[string]interface{}{}
+		m["m"] = m
+		Expect(New(m).ApplyMergePatch(New(map[string]interface{}{"x": 1}))).Should(BeNil())
+		Expect(m["x"]).Should(Equal(1))
+	})
+	Specify("ApplyMergePatch()", func() {
+		x := fromJSON(`{
+			"title": "Goodbye!",
+			"author": {"givenName": "John", "familyName": "Doe"},
+			"tags": ["example", "sample"],
+			"content": "This will be unchanged"
+		}`)
+		patch := fromJSON(`{
+			"title": "Hello!",
+			"phoneNumber": "+01-234-567-8910",
+			"author": {"familyName": null},
+			"tags": ["example"]
+		}`)
+		v := New(x)
+		Expect(v.ApplyMergePatch(New(patch))).Should(BeNil())
+		Expect(x).Should(Equal(fromJSON(`{
+			"title": "Hello!",
+			"author": {"givenName": "John"},
+			"tags": ["example"],
+			"content": "This will be unchanged",
+			"phoneNumber": "+01-234-567-8910"
+		}`)))
+
+		y := &conf{Name: "a", Port: 80}
+		patch = fromJSON(`{"name": null, "port": 81, "tls": {"cert": "c"}, "servers": [{"name": "s"}]}`)
+		Expect(New(y).ApplyMergePatch(New(patch))).Should(BeNil())
+		Expect(*y).Should(Equal(conf{Port: 81, TLS: &tls{"c"}, Servers: []server{{"s", 0}}}))
+
+		err := New(y).ApplyMergePatch(New(map[string]interface{}{"tls": map[string]interface{}{"none": 1}}))
+		Expect(err).Should(BeAssignableToTypeOf((*ErrPath)(nil)))
+		Expect(err.(*ErrPath).Path).Should(Equal("tls.none"))
+	})
+	Specify("NewPatch()", func() {
+		a := fromJSON(`{"a": [1, 2, 3], "b": {"c": 1}, "d/e": 1}`)
+		b := fromJSON(`{"a": [1], "b": {"c": 2, "f": [true]}}`)
+		patch := NewPatch(Diff(New(a), New(b)))
+		data, err := json.Marshal(patch)
+		Expect(err).Should(BeNil())
+		Expect(string(data)).Should(MatchJSON(`[
+			{"op": "replace", "path": "/b/c", "value": 2},
+			{"op": "add", "path": "/b/f", "value": [true]},
+			{"op": "remove", "path": "/d~1e"},
+			{"op": "remove", "path": "/a/2"},
+			{"op": "remove", "path": "/a/1"}
+		]`))
+
+		v := New(a)
+		Expect(v.ApplyPatch(patch)).Should(BeNil())
+		Expect(v.Equal(New(b))).Should(BeTrue())
+	})
+})
@@ -0,0 +1,452 @@
+package value
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathSeg is a segment of path, the index indicates it's written as "[key]".
+type pathSeg struct {
+	key   string
+	index bool
+}
+
+func (s pathSeg) String() string {
+	if s.index {
+		return "[" + s.key + "]"
+	}
+	return s.key
+}
+
+// parsePath parses path like "a[0].b" to segments.
+func parsePath(path string) ([]pathSeg, error) {
+	var segs []pathSeg
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			if len(segs) == 0 || i+1 == len(path) || path[i+1] == '.' || path[i+1] == '[' {
+				return nil, fmt.Errorf("unexpected '.' at %d", i)
+			}
+			i++
+		case '[':
+			j := strings.IndexByte(path[i:], ']')
+			if j < 0 {
+				return nil, fmt.Errorf("unclosed '[' at %d", i)
+			}
+			if j == 1 {
+				return nil, fmt.Errorf("empty '[]' at %d", i)
+			}
+			segs = append(segs, pathSeg{key: path[i+1 : i+j], index: true})
+			i += j + 1
+		default:
+			if i > 0 && path[i-1] != '.' {
+				return nil, fmt.Errorf("unexpected %q at %d", path[i], i)
+			}
+			j := i
+			for j < len(path) && path[j] != '.' && path[j] != '[' {
+				j++
+			}
+			segs = append(segs, pathSeg{key: path[i:j]})
+			i = j
+		}
+	}
+	return segs, nil
+}
+
+func (s pathSeg) idx() (int, error) {
+	idx, err := strconv.Atoi(s.key)
+	if err != nil {
+		return 0, &ErrNotExist{"Value.pathSeg", s.key + " index"}
+	}
+	if idx < 0 {
+		return 0, &ErrOutOfRange{"Value.pathSeg"}
+	}
+	return idx, nil
+}
+
+// mapKey converts s to the key of map which key type is t.
+func (s pathSeg) mapKey(t reflect.Type) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(s.key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s.key, 10, t.Bits())
+		if err != nil {
+			return key, &ErrNotExist{"Value.pathSeg", s.key + " key"}
+		}
+		key.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s.key, 10, t.Bits())
+		if err != nil {
+			return key, &ErrNotExist{"Value.pathSeg", s.key + " key"}
+		}
+		key.SetUint(u)
+	case reflect.Interface:
+		var k interface{} = s.key
+		if s.index {
+			if i, err := strconv.Atoi(s.key); err == nil {
+				k = i
+			}
+		}
+		kv := reflect.ValueOf(k)
+		if !kv.Type().AssignableTo(t) {
+			return key, &ErrUnsupportedKind{"Value.pathSeg", "map key of " + t.String()}
+		}
+		key.Set(kv)
+	default:
+		return key, &ErrUnsupportedKind{"Value.pathSeg", "map key of " + t.Kind().String()}
+	}
+	return key, nil
+}
+
+// GetPath returns the value with the given path.
+//
+// The path is keys joined by '.', and index of array/slice can be written as "[idx]",
+// like "a[0].b" or "a.0.b". Each key is got like Value.Get.
+// It returns ErrPath with the failed segment if any key is not found or can't be got.
+// The got value keeps the source positions of t, see NewWithPositions.
+func (v *Value) GetPath(path string) (*Value, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, &ErrPath{"Value.GetPath", path, "", err, nil}
+	}
+
+	cur := v
+	for _, seg := range segs {
+		next, err := cur.getSeg(seg)
+		if err != nil {
+			return nil, &ErrPath{"Value.GetPath", path, seg.String(), err, cur.positionOf([]pathSeg{seg})}
+		}
+		if cur.pos != nil {
+			kind := indirect(cur.getrv()).Kind()
+			seg.index = kind == reflect.Array || kind == reflect.Slice
+			next.pos, next.at = cur.pos, append(cur.at[:len(cur.at):len(cur.at)], seg)
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+func (v *Value) getSeg(seg pathSeg) (*Value, error) {
+	rv := indirect(v.getrv())
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil, &ErrCannotBeNil{"Value.getSeg"}
+	case reflect.Map:
+		key, err := seg.mapKey(rv.Type().Key())
+		if err != nil {
+			return nil, err
+		}
+		val := rv.MapIndex(key)
+		if !val.IsValid() {
+			return nil, &ErrNotExist{"Value.getSeg", seg.key + " key"}
+		}
+		return &Value{rv: val}, nil
+	case reflect.Array, reflect.Slice:
+		idx, err := seg.idx()
+		if err != nil {
+			return nil, err
+		}
+		if idx >= rv.Len() {
+			return nil, &ErrOutOfRange{"Value.getSeg"}
+		}
+		return &Value{rv: rv.Index(idx)}, nil
+	case reflect.Struct:
+		field := rv.FieldByName(seg.key)
+		if !field.IsValid() {
+			return nil, &ErrNotExist{"Value.getSeg", seg.key + " field"}
+		}
+		return &Value{rv: field}, nil
+	default:
+		return nil, &ErrUnsupportedKind{"Value.getSeg", rv.Kind()}
+	}
+}
+
+// PutPath puts val to the given path, the path is same as Value.GetPath.
+//
+// The missing intermediate values are created, a map[string]interface{} for key
+// and a []interface{} for "[idx]", if the container is typed, it's zero value is used.
+// Nil maps and pointers are allocated, and slices are grown as Value.Put.
+// It returns ErrPath with the failed segment if val can't be put.
+func (v *Value) PutPath(path string, val interface{}) error {
+	segs, err := parsePath(path)
+	if err != nil {
+		return &ErrPath{"Value.PutPath", path, "", err, nil}
+	}
+	if len(segs) == 0 {
+		return v.Set(val)
+	}
+
+	rv, err := putSegs(v.getrv(), segs, reflect.ValueOf(val))
+	if err != nil {
+		if e, ok := err.(*ErrPath); ok {
+			e.Path = path
+		}
+		return err
+	}
+
+	v.rv = rv
+	v.iv = nil
+	return nil
+}
+
+// newContainer returns a container for seg, that used when the value is missing.
+func newContainer(seg pathSeg) reflect.Value {
+	if seg.index {
+		return reflect.ValueOf([]interface{}{})
+	}
+	return reflect.ValueOf(map[string]interface{}{})
+}
+
+func assignTo(val reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !val.IsValid() {
+		return reflect.Zero(t), nil
+	}
+	if !val.Type().AssignableTo(t) {
+		return val, &ErrTypeUnequal{"Value.putSeg", t.Kind(), val.Kind()}
+	}
+	return val, nil
+}
+
+// putSegs puts val to cur with segs, and returns the new value of cur,
+// the caller must set it back if cur is in a container.
+func putSegs(cur reflect.Value, segs []pathSeg, val reflect.Value) (reflect.Value, error) {
+	if len(segs) == 0 {
+		return val, nil
+	}
+
+	seg := segs[0]
+	segErr := func(err error) error {
+		return &ErrPath{"Value.PutPath", "", seg.String(), err, nil}
+	}
+
+	switch cur.Kind() {
+	case reflect.Invalid:
+		return putSegs(newContainer(seg), segs, val)
+
+	case reflect.Interface:
+		if cur.IsNil() {
+			return putSegs(newContainer(seg), segs, val)
+		}
+		return putSegs(cur.Elem(), segs, val)
+
+	case reflect.Ptr:
+		if cur.IsNil() {
+			cur = reflect.New(cur.Type().Elem())
+		}
+		elem := cur.Elem()
+		nv, err := putSegs(elem, segs, val)
+		if err != nil {
+			return cur, err
+		}
+		elem.Set(nv)
+		return cur, nil
+
+	case reflect.Map:
+		if cur.IsNil() {
+			cur = reflect.MakeMap(cur.Type())
+		}
+		key, err := seg.mapKey(cur.Type().Key())
+		if err != nil {
+			return cur, segErr(err)
+		}
+		child := cur.MapIndex(key)
+		if !child.IsValid() {
+			child = reflect.Zero(cur.Type().Elem())
+		}
+		nv, err := putSegs(child, segs[1:], val)
+		if err != nil {
+			return cur, err
+		}
+		if nv, err = assignTo(nv, cur.Type().Elem()); err != nil {
+			return cur, segErr(err)
+		}
+		cur.SetMapIndex(key, nv)
+		return cur, nil
+
+	case reflect.Slice:
+		idx, err := seg.idx()
+		if err != nil {
+			return cur, segErr(err)
+		}
+		for cur.Len() <= idx {
+			cur = reflect.Append(cur, reflect.Zero(cur.Type().Elem()))
+		}
+		return cur, putElem(cur.Index(idx), segs[1:], val, segErr)
+
+	case reflect.Array:
+		idx, err := seg.idx()
+		if err != nil {
+			return cur, segErr(err)
+		}
+		if idx >= cur.Len() {
+			return cur, segErr(&ErrOutOfRange{"Value.putSeg"})
+		}
+		cur = settable(cur)
+		return cur, putElem(cur.Index(idx), segs[1:], val, segErr)
+
+	case reflect.Struct:
+		cur = settable(cur)
+		field := cur.FieldByName(seg.key)
+		if !field.IsValid() {
+			return cur, segErr(&ErrNotExist{"Value.putSeg", seg.key + " field"})
+		}
+		if !field.CanSet() {
+			return cur, segErr(&ErrCannotSet{"Value.putSeg"})
+		}
+		return cur, putElem(field, segs[1:], val, segErr)
+
+	default:
+		return cur, segErr(&ErrUnsupportedKind{"Value.putSeg", cur.Kind()})
+	}
+}
+
+// putElem puts val to the settable elem with segs.
+func putElem(elem reflect.Value, segs []pathSeg, val reflect.Value, segErr func(error) error) error {
+	nv, err := putSegs(elem, segs, val)
+	if err != nil {
+		return err
+	}
+	if nv, err = assignTo(nv, elem.Type()); err != nil {
+		return segErr(err)
+	}
+	elem.Set(nv)
+	return nil
+}
+
+// DeletePath deletes the value of the given path, the path is same as Value.GetPath.
+//
+// The last key is deleted like Value.Delete, the map key is deleted,
+// the slice element is removed, and the array element or struct field is set to zero value.
+// The slice is shifted in place if it's settable, like a struct field, otherwise it's copied.
+// It returns ErrPath with the failed segment if any key is not found, which wraps ErrNotExist.
+func (v *Value) DeletePath(path string) error {
+	segs, err := parsePath(path)
+	if err != nil {
+		return &ErrPath{"Value.DeletePath", path, "", err, nil}
+	}
+	if len(segs) == 0 {
+		return &ErrPath{"Value.DeletePath", path, "", &ErrNotExist{"Value.DeletePath", "key of empty path"}, nil}
+	}
+
+	rv, err := deleteSegs(v.getrv(), segs)
+	if err != nil {
+		if e, ok := err.(*ErrPath); ok {
+			e.Path = path
+		}
+		return err
+	}
+
+	v.rv = rv
+	v.iv = nil
+	return nil
+}
+
+// deleteSegs deletes the last of segs from cur, and returns the new value of cur,
+// the caller must set it back if cur is in a container.
+func deleteSegs(cur reflect.Value, segs []pathSeg) (reflect.Value, error) {
+	seg := segs[0]
+	segErr := func(err error) error {
+		return &ErrPath{"Value.DeletePath", "", seg.String(), err, nil}
+	}
+
+	if (cur.Kind() == reflect.Interface || cur.Kind() == reflect.Ptr) && cur.IsNil() {
+		return cur, segErr(&ErrNotExist{"Value.deleteSeg", seg.key + " of nil"})
+	}
+	switch cur.Kind() {
+	case reflect.Interface:
+		return deleteSegs(cur.Elem(), segs)
+	case reflect.Ptr:
+		elem := cur.Elem()
+		nv, err := deleteSegs(elem, segs)
+		if err != nil {
+			return cur, err
+		}
+		elem.Set(nv)
+		return cur, nil
+	}
+
+	last := len(segs) == 1
+	switch cur.Kind() {
+	case reflect.Map:
+		key, err := seg.mapKey(cur.Type().Key())
+		if err != nil {
+			return cur, segErr(err)
+		}
+		child := cur.MapIndex(key)
+		if !child.IsValid() {
+			return cur, segErr(&ErrNotExist{"Value.deleteSeg", seg.key + " key"})
+		}
+		if last {
+			cur.SetMapIndex(key, reflect.Value{})
+			return cur, nil
+		}
+		nv, err := deleteSegs(child, segs[1:])
+		if err != nil {
+			return cur, err
+		}
+		if nv, err = assignTo(nv, cur.Type().Elem()); err != nil {
+			return cur, segErr(err)
+		}
+		cur.SetMapIndex(key, nv)
+		return cur, nil
+
+	case reflect.Slice, reflect.Array:
+		idx, err := seg.idx()
+		if err == nil && idx >= cur.Len() {
+			err = &ErrNotExist{"Value.deleteSeg", seg.key + " index"}
+		}
+		if err != nil {
+			return cur, segErr(err)
+		}
+		if last && cur.Kind() == reflect.Slice {
+			return removeIndex(cur, idx), nil
+		}
+		cur = settable(cur)
+		return cur, deleteElem(cur.Index(idx), segs[1:], segErr)
+
+	case reflect.Struct:
+		cur = settable(cur)
+		field := cur.FieldByName(seg.key)
+		if !field.IsValid() {
+			return cur, segErr(&ErrNotExist{"Value.deleteSeg", seg.key + " field"})
+		}
+		if !field.CanSet() {
+			return cur, segErr(&ErrCannotSet{"Value.deleteSeg"})
+		}
+		return cur, deleteElem(field, segs[1:], segErr)
+
+	default:
+		return cur, segErr(&ErrUnsupportedKind{"Value.deleteSeg", cur.Kind()})
+	}
+}
+
+// deleteElem deletes segs from the settable elem, or sets it to zero value if segs is empty.
+func deleteElem(elem reflect.Value, segs []pathSeg, segErr func(error) error) error {
+	if len(segs) == 0 {
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	}
+	nv, err := deleteSegs(elem, segs)
+	if err != nil {
+		return err
+	}
+	if nv, err = assignTo(nv, elem.Type()); err != nil {
+		return segErr(err)
+	}
+	elem.Set(nv)
+	return nil
+}
+
+// settable returns rv itself if it's settable, or a settable copy of rv.
+func settable(rv reflect.Value) reflect.Value {
+	if rv.CanSet() {
+		return rv
+	}
+	cp := reflect.New(rv.Type()).Elem()
+	cp.Set(rv)
+	return cp
+}
@@ -0,0 +1,193 @@
+package value
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Paths", func() {
+	Context("with parsePath()", func() {
+		Specify("valid path", func() {
+			Expect(parsePath("")).To(BeEmpty())
+			Expect(parsePath("a[0].b")).To(Equal([]pathSeg{{"a", false}, {"0", true}, {"b", false}}))
+			Expect(parsePath("[1][2].c.3")).To(Equal([]pathSeg{{"1", true}, {"2", true}, {"c", false}, {"3", false}}))
+		})
+		Specify("invalid path", func() {
+			for _, path := range []string{".a", "a.", "a..b", "a.[0]", "a[0", "a[]", "a[0]b"} {
+				_, err := parsePath(path)
+				Expect(err).ShouldNot(BeNil(), path)
+			}
+		})
+	})
+
+	Context("with GetPath()", func() {
+		type xx struct {
+			X []int
+		}
+		x := map[string]interface{}{
+			"a": []interface{}{
+				map[string]interface{}{"b": 1},
+			},
+			"c": &xx{X: []int{1, 2}},
+			"d": map[int]string{1: "one"},
+		}
+		v := New(x)
+
+		Specify("from nested map, slice, struct and ptr", func() {
+			Expect(v.MustGetPath("a[0].b").Int()).Should(Equal(1))
+			Expect(v.MustGetPath("a.0.b").Int()).Should(Equal(1))
+			Expect(v.MustGetPath("c.X[1]").Int()).Should(Equal(2))
+			Expect(v.MustGetPath("d[1]").String()).Should(Equal("one"))
+			Expect(v.MustGetPath("")).Should(Equal(v))
+		})
+		Specify("not exist", func() {
+			_, err := v.GetPath("a[0].x")
+			Expect(err).To(BeAssignableToTypeOf((*ErrPath)(nil)))
+			Expect(err.(*ErrPath).Segment).To(Equal("x"))
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+
+			_, err = v.GetPath("c.X[2]")
+			Expect(err.(*ErrPath).Segment).To(Equal("[2]"))
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrOutOfRange)(nil)))
+
+			_, err = v.GetPath("a[0].b.c")
+			Expect(err.(*ErrPath).Segment).To(Equal("c"))
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
+		})
+		Specify("invalid path", func() {
+			_, err := v.GetPath("a[0")
+			Expect(err).To(BeAssignableToTypeOf((*ErrPath)(nil)))
+			Expect(func() { v.MustGetPath("a[0") }).Should(Panic())
+		})
+	})
+
+	Context("with PutPath()", func() {
+		Specify("to nested map and slice", func() {
+			x := map[string]interface{}{
+				"a": []interface{}{1},
+			}
+			v := New(x)
+			Expect(v.PutPath("a[2]", 3)).Should(BeNil())
+			Expect(v.PutPath("b.c[1].d", "d")).Should(BeNil())
+
+			Expect(x["a"]).Should(Equal([]interface{}{1, nil, 3}))
+			Expect(x["b"]).Should(Equal(map[string]interface{}{
+				"c": []interface{}{nil, map[string]interface{}{"d": "d"}},
+			}))
+		})
+		Specify("to nil value", func() {
+			v := New(nil)
+			Expect(v.PutPath("a.b", 1)).Should(BeNil())
+			Expect(v.Interface()).Should(Equal(map[string]interface{}{
+				"a": map[string]interface{}{"b": 1},
+			}))
+		})
+		Specify("to typed struct and ptr", func() {
+			type xx struct {
+				A [2]int
+				B map[string]*xx
+				C []string
+			}
+			x := xx{}
+			v := New(&x)
+			Expect(v.PutPath("A[1]", 1)).Should(BeNil())
+			Expect(v.PutPath("B.y.C[1]", "c")).Should(BeNil())
+			Expect(x.A).Should(Equal([2]int{0, 1}))
+			Expect(x.B["y"].C).Should(Equal([]string{"", "c"}))
+		})
+		Specify("failed", func() {
+			x := struct {
+				A [1]int
+				B int
+				c int
+			}{}
+			v := New(&x)
+
+			err := v.PutPath("A[1]", 1)
+			Expect(err).To(BeAssignableToTypeOf((*ErrPath)(nil)))
+			Expect(err.(*ErrPath).Path).To(Equal("A[1]"))
+			Expect(err.(*ErrPath).Segment).To(Equal("[1]"))
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrOutOfRange)(nil)))
+
+			err = v.PutPath("B", "b")
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrTypeUnequal)(nil)))
+
+			err = v.PutPath("c", 1)
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrCannotSet)(nil)))
+
+			err = v.PutPath("D", 1)
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+
+			err = v.PutPath("B.x", 1)
+			Expect(err.(*ErrPath).Segment).To(Equal("x"))
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
+		})
+	})
+
+	Context("with DeletePath()", func() {
+		Specify("from nested map, slice, struct and ptr", func() {
+			type xx struct {
+				A [2]int
+				B map[string]*xx
+				C []string
+			}
+			x := map[string]interface{}{
+				"a": []interface{}{1, map[string]interface{}{"b": 2, "c": 3}, 4},
+				"x": xx{A: [2]int{1, 2}, B: map[string]*xx{"y": {C: []string{"c", "d"}}}},
+			}
+			a := x["a"].([]interface{})
+			v := New(x)
+			Expect(v.DeletePath("a[1].b")).Should(BeNil())
+			Expect(v.DeletePath("a[0]")).Should(BeNil())
+			Expect(v.DeletePath("x.A[1]")).Should(BeNil())
+			Expect(v.DeletePath("x.B.y.C[0]")).Should(BeNil())
+
+			Expect(x["a"]).Should(Equal([]interface{}{map[string]interface{}{"c": 3}, 4}))
+			Expect(a).Should(HaveLen(3))
+			Expect(a[0]).Should(Equal(1))
+			Expect(x["x"].(xx).A).Should(Equal([2]int{1, 0}))
+			Expect(x["x"].(xx).B["y"].C).Should(Equal([]string{"d"}))
+
+			Expect(v.DeletePath("x.B")).Should(BeNil())
+			Expect(x["x"].(xx).B).Should(BeNil())
+			Expect(v.DeletePath("x")).Should(BeNil())
+			Expect(x).ShouldNot(HaveKey("x"))
+		})
+		Specify("failed", func() {
+			x := struct {
+				A []int
+				B map[string]int
+				c int
+			}{A: []int{1}}
+			v := New(&x)
+
+			err := v.DeletePath("A[1]")
+			Expect(err).To(BeAssignableToTypeOf((*ErrPath)(nil)))
+			Expect(err.(*ErrPath).Path).To(Equal("A[1]"))
+			Expect(err.(*ErrPath).Segment).To(Equal("[1]"))
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+
+			err = v.DeletePath("B.b")
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+
+			err = v.DeletePath("c")
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrCannotSet)(nil)))
+
+			err = v.DeletePath("D")
+			Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+
+			err = v.DeletePath("")
+			Expect(err).To(BeAssignableToTypeOf((*ErrPath)(nil)))
+
+			y := struct {
+				P *struct{ B int }
+				I interface{}
+			}{}
+			for _, path := range []string{"P.B", "I.b"} {
+				err = New(&y).DeletePath(path)
+				Expect(err.(*ErrPath).Path).To(Equal(path))
+				Expect(err.(*ErrPath).Err).To(BeAssignableToTypeOf((*ErrNotExist)(nil)), path)
+			}
+		})
+	})
+})
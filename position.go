@@ -0,0 +1,70 @@
+package value
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Position is a position in source, like a line of YAML file.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return "line " + strconv.Itoa(p.Line) + ", column " + strconv.Itoa(p.Column)
+}
+
+// Positions are the source positions of values keyed by path, like "a[0].b", and "" for the root.
+// The key which is empty or contains '.', '[', ']' or '"' is quoted like `a["b.c"]`, see PositionKey.
+type Positions map[string]Position
+
+// PositionKey joins the map key to path as the keys of Positions.
+func PositionKey(path, key string) string {
+	return path + changeKey(key, path == "")
+}
+
+// NewWithPositions new a Value from v with the source positions of its values,
+// the positions are kept by the values got with Value.GetPath,
+// and are reported in the ErrPath of Value.ConvTo.
+func NewWithPositions(v interface{}, pos Positions) *Value {
+	return &Value{iv: v, pos: pos}
+}
+
+// Position returns the source position of the value at path,
+// or of its nearest ancestor if the value has no position.
+// It returns false if t has no positions.
+func (v *Value) Position(path string) (Position, bool) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return Position{}, false
+	}
+	// normalize the indexes like GetPath
+	cur := v
+	for i, seg := range segs {
+		kind := indirect(cur.getrv()).Kind()
+		segs[i].index = kind == reflect.Array || kind == reflect.Slice
+		if cur, err = cur.getSeg(seg); err != nil {
+			break
+		}
+	}
+	p := v.positionOf(segs)
+	if p == nil {
+		return Position{}, false
+	}
+	return *p, true
+}
+
+// positionOf returns the position of segs relative to t, or of its nearest ancestor.
+func (v *Value) positionOf(segs []pathSeg) *Position {
+	if v.pos == nil {
+		return nil
+	}
+	full := append(v.at[:len(v.at):len(v.at)], segs...)
+	for i := len(full); i >= 0; i-- {
+		if p, ok := v.pos[changePath(full[:i])]; ok {
+			return &p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package value
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Positions", func() {
+	x := map[string]interface{}{
+		"a": []interface{}{
+			map[string]interface{}{"b": "x"},
+		},
+	}
+	pos := Positions{
+		"":       {1, 1},
+		"a":      {2, 1},
+		"a[0]":   {3, 3},
+		"a[0].b": {3, 6},
+	}
+
+	Specify("Position()", func() {
+		v := NewWithPositions(x, pos)
+		position := func(v *Value, path string) Position {
+			p, ok := v.Position(path)
+			Expect(ok).Should(BeTrue(), path)
+			return p
+		}
+		Expect(position(v, "a[0].b")).Should(Equal(Position{3, 6}))
+		Expect(position(v, "a.0.b")).Should(Equal(Position{3, 6}))
+		Expect(position(v, "a[0].c")).Should(Equal(Position{3, 3}))
+		Expect(position(v, "")).Should(Equal(Position{1, 1}))
+
+		sub := v.MustGetPath("a.0")
+		Expect(position(sub, "b")).Should(Equal(Position{3, 6}))
+		Expect(position(sub, "")).Should(Equal(Position{3, 3}))
+
+		_, ok := New(x).Position("a")
+		Expect(ok).Should(BeFalse())
+		_, ok = v.Position("a[")
+		Expect(ok).Should(BeFalse())
+	})
+	Specify("in errors", func() {
+		v := NewWithPositions(x, pos)
+		_, err := v.GetPath("a[0].c")
+		Expect(err.(*ErrPath).Pos).Should(Equal(&Position{3, 3}))
+
+		var y struct {
+			A []struct{ B int }
+		}
+		err = v.ConvTo(&y)
+		e := err.(*ErrMulti).Errs[0].(*ErrPath)
+		Expect(e.Path).Should(Equal("a[0].b"))
+		Expect(e.Pos).Should(Equal(&Position{3, 6}))
+
+		err = v.MustGetPath("a[0]").ConvTo(&y.A[0])
+		Expect(err.(*ErrMulti).Errs[0].(*ErrPath).Pos).Should(Equal(&Position{3, 6}))
+
+		err = New(x).ConvTo(&y)
+		Expect(err.(*ErrMulti).Errs[0].(*ErrPath).Pos).Should(BeNil())
+	})
+	Specify("of dotted keys", func() {
+		Expect(PositionKey("", "a")).Should(Equal("a"))
+		Expect(PositionKey("a[0]", "b")).Should(Equal("a[0].b"))
+		Expect(PositionKey("", "a.b")).Should(Equal(`["a.b"]`))
+		Expect(PositionKey("a", "")).Should(Equal(`a[""]`))
+
+		x := map[string]interface{}{
+			"a.b": "x",
+			"a":   map[string]interface{}{"b": "y"},
+		}
+		v := NewWithPositions(x, Positions{
+			`["a.b"]`: {1, 1},
+			"a":       {2, 1},
+			"a.b":     {3, 3},
+		})
+		var y struct {
+			AB int `value:"a.b"`
+			A  struct{ B int }
+		}
+		var got []Position
+		for _, err := range v.ConvTo(&y).(*ErrMulti).Errs {
+			Expect(err.(*ErrPath).Path).Should(Equal("a.b"))
+			got = append(got, *err.(*ErrPath).Pos)
+		}
+		Expect(got).Should(ConsistOf(Position{1, 1}, Position{3, 3}))
+	})
+})
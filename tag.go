@@ -0,0 +1,92 @@
+package value
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fallbackTags are the tags used in turn if a struct field has no value tag.
+var fallbackTags = []string{"json", "yaml", "mapstructure"}
+
+// tagOptions is the options of value tag, the comma-separated part after name.
+type tagOptions string
+
+// parseTag splits value tag into the name and options.
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+// fieldTag returns the value tag of field,
+// or the first present one of json, yaml and mapstructure tags if no value tag.
+func fieldTag(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("value"); ok {
+		return tag
+	}
+	for _, key := range fallbackTags {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			return tag
+		}
+	}
+	return ""
+}
+
+// split returns the comma-separated options, a value can be quoted with single quotes
+// to contain commas like `default='a,b'`, and a single quote in it is doubled.
+func (o tagOptions) split() []string {
+	var opts []string
+	quoted, start := false, 0
+	for i := 0; i < len(o); i++ {
+		switch o[i] {
+		case '\'':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				opts = append(opts, string(o[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if start < len(o) {
+		opts = append(opts, string(o[start:]))
+	}
+	return opts
+}
+
+// Has reports whether the options contains the option name.
+func (o tagOptions) Has(name string) bool {
+	for _, opt := range o.split() {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value of option "name=value", the quoted value is unquoted.
+func (o tagOptions) Get(name string) (string, bool) {
+	prefix := name + "="
+	for _, opt := range o.split() {
+		if !strings.HasPrefix(opt, prefix) {
+			continue
+		}
+		val := opt[len(prefix):]
+		if len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'' {
+			val = strings.Replace(val[1:len(val)-1], "''", "'", -1)
+		}
+		return val, true
+	}
+	return "", false
+}
+
+// fieldDefault returns the default value of field,
+// which is set by tag `default:"..."` or `value:",default=..."`.
+func fieldDefault(field reflect.StructField) (string, bool) {
+	if def, ok := field.Tag.Lookup("default"); ok {
+		return def, true
+	}
+	_, opts := parseTag(fieldTag(field))
+	return opts.Get("default")
+}
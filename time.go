@@ -0,0 +1,136 @@
+package value
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	// TimeLayout is the layout of time.Time formatted by Value.String,
+	// it's also tried when converting string to time.Time.
+	TimeLayout = time.RFC3339Nano
+
+	// TimeLayouts are the layouts tried in order when converting string to time.Time,
+	// after TimeLayout, if ConvOptions.TimeLayouts is not set.
+	TimeLayouts = []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC850,
+		time.RFC822Z,
+		time.RFC822,
+		time.RubyDate,
+		time.UnixDate,
+		time.ANSIC,
+		"Mon Jan 2 15:04:05 -0700 MST 2006",
+		"2006-01-02 15:04:05.999999999 -0700 MST",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	}
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// timeFormat is the format to convert to time.Time.
+type timeFormat struct {
+	layouts []string      // nil for TimeLayout and TimeLayouts
+	epoch   time.Duration // unit of numeric source
+}
+
+// field returns the time format overridden by the tag options of field,
+// `layout=...` and `epoch=ms`, the layout containing commas is quoted
+// like `layout='Mon, 02 Jan 2006'`.
+func (f timeFormat) field(field reflect.StructField) (timeFormat, error) {
+	_, opts := parseTag(fieldTag(field))
+	if layout, ok := opts.Get("layout"); ok {
+		f.layouts = []string{layout}
+	}
+	if epoch, ok := opts.Get("epoch"); ok {
+		unit, err := time.ParseDuration("1" + epoch)
+		if err != nil || unit <= 0 {
+			return f, &ErrUnsupportedKind{"Value.ConvTo", "epoch unit " + epoch}
+		}
+		f.epoch = unit
+	}
+	return f, nil
+}
+
+// convToTimeTime converts t to dst of time.Time, the string is parsed with the layouts of f in turn,
+// and the number is the time since Unix epoch in the unit of f, that results in UTC.
+func (v *Value) convToTimeTime(f timeFormat, dst reflect.Value) error {
+	rv := indirect(v.getrv())
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := v.toInt("Value.convToTimeTime", reflect.Int64)
+		if err != nil {
+			return err
+		}
+		t, err := unixTime(n, f.epoch)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		sec, frac := math.Modf(rv.Float() * float64(f.epoch) / float64(time.Second))
+		if math.IsNaN(sec) || math.Abs(sec) >= math.MaxInt64/2 {
+			return &ErrNumOverflow{"Value.convToTimeTime", reflect.Int64, rv.Float()}
+		}
+		dst.Set(reflect.ValueOf(time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC()))
+		return nil
+	}
+
+	s, err := v.String()
+	if err != nil {
+		return err
+	}
+	layouts := f.layouts
+	if layouts == nil {
+		layouts = append([]string{TimeLayout}, TimeLayouts...)
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %q as time with layouts %q", s, layouts)
+}
+
+// unixTime returns the UTC time of n in unit since Unix epoch.
+// It returns ErrNumOverflow if the seconds of n overflow int64.
+func unixTime(n int64, unit time.Duration) (time.Time, error) {
+	if unit >= time.Second {
+		k := int64(unit / time.Second)
+		if n > math.MaxInt64/k || n < math.MinInt64/k {
+			return time.Time{}, &ErrNumOverflow{"Value.convToTimeTime", reflect.Int64, n}
+		}
+		return time.Unix(n*k, 0).UTC(), nil
+	}
+	per := int64(time.Second / unit)
+	return time.Unix(n/per, n%per*int64(unit)).UTC(), nil
+}
+
+// unixOf returns the time since Unix epoch of t in unit, the fraction is truncated.
+func unixOf(t time.Time, unit time.Duration) int64 {
+	if unit >= time.Second {
+		return t.Unix() / int64(unit/time.Second)
+	}
+	return t.Unix()*int64(time.Second/unit) + int64(t.Nanosecond())/int64(unit)
+}
+
+// format formats t with the first layout of f, or as the number since Unix epoch if f has only epoch,
+// and with TimeLayout if f has neither.
+func (f timeFormat) format(t time.Time) (string, bool) {
+	switch {
+	case len(f.layouts) > 0:
+		return t.Format(f.layouts[0]), true
+	case f.epoch > 0:
+		return strconv.FormatInt(unixOf(t, f.epoch), 10), false
+	}
+	return t.Format(TimeLayout), true
+}
@@ -0,0 +1,130 @@
+package value
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Time", func() {
+	at := time.Date(2019, 11, 1, 19, 13, 55, 0, time.UTC)
+
+	Specify("with default layouts", func() {
+		for _, x := range []string{
+			"2019-11-01T19:13:55Z",
+			"Fri, 01 Nov 2019 19:13:55 UTC",
+			"Fri Nov 1 19:13:55 +0000 UTC 2019",
+			"2019-11-01 19:13:55",
+		} {
+			var y time.Time
+			Expect(New(x).ConvTo(&y)).Should(BeNil(), x)
+			Expect(y.Equal(at)).Should(BeTrue(), x)
+		}
+
+		var y time.Time
+		Expect(New("2019-11-01").ConvTo(&y)).Should(BeNil())
+		Expect(y).Should(Equal(time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(New("01/11/2019").ConvTo(&y)).ShouldNot(BeNil())
+	})
+	Specify("with layouts of options and tag", func() {
+		var y struct {
+			A time.Time
+			B []time.Time `value:"b,layout='02 Jan 06, 15:04'"`
+			C time.Time   `value:",default=01/11/2019"`
+		}
+		x := map[string]interface{}{
+			"a": "01/11/2019 19:13:55",
+			"b": []string{"01 Nov 19, 19:13"},
+		}
+		opts := ConvOptions{TimeLayouts: []string{"02/01/2006 15:04:05", "02/01/2006"}}
+		Expect(New(x).ConvToWithOptions(&y, opts)).Should(BeNil())
+		Expect(y.A).Should(Equal(at))
+		Expect(y.B).Should(Equal([]time.Time{at.Truncate(time.Minute)}))
+		Expect(y.C).Should(Equal(at.Truncate(24 * time.Hour)))
+
+		Expect(New("2019-11-01T19:13:55Z").ConvToWithOptions(&y.A, opts)).ShouldNot(BeNil())
+	})
+	Specify("with layout of tag not applied to nested fields", func() {
+		type inner struct {
+			T time.Time
+			D time.Time `value:"d,default=2019-11-01T19:13:55Z"`
+		}
+		var y struct {
+			In  inner   `value:"in,layout=2006"`
+			Ins []inner `value:"ins,epoch=ms"`
+		}
+		x := map[string]interface{}{
+			"in":  map[string]interface{}{"t": "2019-11-01T19:13:55Z"},
+			"ins": []interface{}{map[string]interface{}{"t": at.Unix()}},
+		}
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.In).Should(Equal(inner{at, at}))
+		Expect(y.Ins).Should(Equal([]inner{{at, at}}))
+	})
+	Specify("from Unix epoch", func() {
+		var y struct {
+			S  time.Time
+			F  time.Time
+			Ms time.Time  `value:"ms,epoch=ms"`
+			Ns *time.Time `value:"ns,epoch=ns"`
+		}
+		x := map[string]interface{}{
+			"s":  at.Unix(),
+			"f":  float64(at.Unix()) + 0.5,
+			"ms": uint64(at.UnixNano() / 1e6),
+			"ns": at.UnixNano(),
+		}
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.S).Should(Equal(at))
+		Expect(y.F).Should(Equal(at.Add(500 * time.Millisecond)))
+		Expect(y.Ms).Should(Equal(at))
+		Expect(*y.Ns).Should(Equal(at))
+
+		var z time.Time
+		Expect(New(at.UnixNano()/1e3).ConvToWithOptions(&z, ConvOptions{EpochUnit: time.Microsecond})).Should(BeNil())
+		Expect(z).Should(Equal(at))
+		Expect(New(-1).ConvTo(&z)).Should(BeNil())
+		Expect(z).Should(Equal(time.Unix(-1, 0).UTC()))
+
+		var bad struct {
+			T time.Time `value:"t,epoch=day"`
+		}
+		Expect(New(map[string]interface{}{"t": 1}).ConvTo(&bad)).ShouldNot(BeNil())
+
+		var hours struct {
+			T time.Time `value:"t,epoch=h"`
+		}
+		err := New(map[string]interface{}{"t": int64(math.MaxInt64 / 60)}).ConvTo(&hours)
+		Expect(err.(*ErrMulti).Errs[0].(*ErrPath).Err).Should(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+	})
+	Specify("to string with layout and epoch of tag", func() {
+		type times struct {
+			D  time.Time  `value:"d,layout=2006-01-02"`
+			Ms time.Time  `value:"ms,epoch=ms"`
+			P  *time.Time `value:"p,layout='Jan 2, 2006 15:04'"`
+			T  time.Time
+			In struct{ T time.Time } `value:"in,layout=2006"`
+		}
+		day, min := at.Truncate(24*time.Hour), at.Truncate(time.Minute)
+		x := times{D: day, Ms: at.Add(time.Millisecond), P: &min, T: at}
+		x.In.T = at
+		s, err := New(x).String()
+		Expect(err).Should(BeNil())
+		Expect(s).Should(Equal(`{"d":"2019-11-01","ms":1572635635001,"p":"Nov 1, 2019 19:13",` +
+			`"T":"2019-11-01T19:13:55Z","in":{"T":"2019-11-01T19:13:55Z"}}`))
+
+		var y times
+		Expect(ConvTo(s, &y)).Should(BeNil(), s)
+		Expect(y).Should(Equal(x))
+	})
+	Specify("to string", func() {
+		Expect(New(at).String()).Should(Equal("2019-11-01T19:13:55Z"))
+		Expect(New(&at).String()).Should(Equal("2019-11-01T19:13:55Z"))
+
+		var y time.Time
+		Expect(ConvTo(New(at).MustString(), &y)).Should(BeNil())
+		Expect(y).Should(Equal(at))
+	})
+})
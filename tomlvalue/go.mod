@@ -0,0 +1,21 @@
+module github.com/helloyi/go-value/tomlvalue
+
+go 1.20
+
+require (
+	github.com/helloyi/go-value v0.0.0-20261017202323-95bc829055cf
+	github.com/onsi/ginkgo v1.10.3
+	github.com/onsi/gomega v1.7.1
+	github.com/pelletier/go-toml/v2 v2.0.9
+)
+
+require (
+	github.com/hpcloud/tail v1.0.0 // indirect
+	github.com/maltegrosse/go-bytesize v0.0.0-20151001220322-5990f52c6ad6 // indirect
+	golang.org/x/net v0.0.0-20180906233101-161cd47e91fd // indirect
+	golang.org/x/sys v0.0.0-20180909124046-d0be0721c37e // indirect
+	golang.org/x/text v0.3.0 // indirect
+	gopkg.in/fsnotify.v1 v1.4.7 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v2 v2.2.4 // indirect
+)
@@ -0,0 +1,120 @@
+// Package tomlvalue loads TOML to value.Value,
+// it's a separate module, so that the module of package value doesn't require TOML.
+package tomlvalue
+
+import (
+	"strconv"
+
+	value "github.com/helloyi/go-value"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// FromTOML new a Value from TOML data.
+//
+// The tables are decoded to map[string]interface{}, the arrays are decoded to []interface{},
+// and the scalars are decoded as go-toml.
+// The source positions of the keys and tables are kept, see value.NewWithPositions.
+func FromTOML(data []byte) (*value.Value, error) {
+	var x map[string]interface{}
+	if err := toml.Unmarshal(data, &x); err != nil {
+		return nil, err
+	}
+	pos, err := positions(data)
+	if err != nil {
+		return nil, err
+	}
+	return value.NewWithPositions(x, pos), nil
+}
+
+// positions returns the positions of the keys and tables of TOML data,
+// the array tables are indexed like "a[0].b".
+func positions(data []byte) (value.Positions, error) {
+	p := &unstable.Parser{}
+	p.Reset(data)
+
+	pos := value.Positions{}
+	arrays := map[string]int{} // count of array tables
+	table := ""
+	for p.NextExpression() {
+		e := p.Expression()
+		switch e.Kind {
+		case unstable.Table:
+			keys, first := keysOf(e)
+			table = resolve("", keys, arrays)
+			pos[table] = position(p, first)
+
+		case unstable.ArrayTable:
+			keys, first := keysOf(e)
+			base := value.PositionKey(resolve("", keys[:len(keys)-1], arrays), keys[len(keys)-1])
+			arrays[base]++
+			table = base + "[" + strconv.Itoa(arrays[base]-1) + "]"
+			pos[table] = position(p, first)
+
+		case unstable.KeyValue:
+			keyValue(p, e, table, pos)
+		}
+	}
+	return pos, p.Error()
+}
+
+// keyValue records the position of key value e in table, and the keys of inline tables in its value.
+func keyValue(p *unstable.Parser, e *unstable.Node, table string, pos value.Positions) {
+	keys, first := keysOf(e)
+	path := table
+	for _, key := range keys {
+		path = value.PositionKey(path, key)
+	}
+	pos[path] = position(p, first)
+	val(p, e.Value(), path, pos)
+}
+
+func val(p *unstable.Parser, n *unstable.Node, path string, pos value.Positions) {
+	switch n.Kind {
+	case unstable.InlineTable:
+		it := n.Children()
+		for it.Next() {
+			keyValue(p, it.Node(), path, pos)
+		}
+	case unstable.Array:
+		it := n.Children()
+		for i := 0; it.Next(); i++ {
+			elem := it.Node()
+			elemPath := path + "[" + strconv.Itoa(i) + "]"
+			if elem.Raw.Length > 0 {
+				pos[elemPath] = position(p, elem)
+			}
+			val(p, elem, elemPath, pos)
+		}
+	}
+}
+
+// keysOf returns the dotted keys of e and the first key node.
+func keysOf(e *unstable.Node) ([]string, *unstable.Node) {
+	var keys []string
+	var first *unstable.Node
+	it := e.Key()
+	for it.Next() {
+		if first == nil {
+			first = it.Node()
+		}
+		keys = append(keys, string(it.Node().Data))
+	}
+	return keys, first
+}
+
+// resolve joins keys to path, the array tables are resolved to their last elements.
+func resolve(path string, keys []string, arrays map[string]int) string {
+	for _, key := range keys {
+		path = value.PositionKey(path, key)
+		if n, ok := arrays[path]; ok {
+			path += "[" + strconv.Itoa(n-1) + "]"
+		}
+	}
+	return path
+}
+
+func position(p *unstable.Parser, n *unstable.Node) value.Position {
+	s := p.Shape(n.Raw)
+	return value.Position{Line: s.Start.Line, Column: s.Start.Column}
+}
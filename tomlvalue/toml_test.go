@@ -0,0 +1,71 @@
+package tomlvalue
+
+import (
+	"errors"
+	"time"
+
+	value "github.com/helloyi/go-value"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FromTOML", func() {
+	data := []byte(`title = "t"
+owner = { name = "o", since = 1979-05-27T07:32:00Z }
+
+[[servers]]
+name = "a"
+port = 80
+
+[[servers]]
+name = "b"
+port = "eighty"
+tags = ["x", "y"]
+
+[servers.limits]
+conns = 10
+`)
+	pos := func(v *value.Value, path string) value.Position {
+		p, ok := v.Position(path)
+		Expect(ok).Should(BeTrue(), path)
+		return p
+	}
+
+	Specify("values", func() {
+		v, err := FromTOML(data)
+		Expect(err).Should(BeNil())
+		Expect(v.MustGetPath("title").MustString()).Should(Equal("t"))
+		Expect(v.MustGetPath("owner.since").Interface()).Should(Equal(time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)))
+		Expect(v.MustGetPath("servers[1].limits.conns").MustInt()).Should(Equal(10))
+	})
+	Specify("positions", func() {
+		v, err := FromTOML(data)
+		Expect(err).Should(BeNil())
+		Expect(pos(v, "title")).Should(Equal(value.Position{Line: 1, Column: 1}))
+		Expect(pos(v, "owner.since")).Should(Equal(value.Position{Line: 2, Column: 23}))
+		Expect(pos(v, "servers[0]")).Should(Equal(value.Position{Line: 4, Column: 3}))
+		Expect(pos(v, "servers[1].port")).Should(Equal(value.Position{Line: 10, Column: 1}))
+		Expect(pos(v, "servers[1].tags[1]")).Should(Equal(value.Position{Line: 11, Column: 14}))
+		Expect(pos(v, "servers[1].limits.conns")).Should(Equal(value.Position{Line: 14, Column: 1}))
+		Expect(pos(v, "servers[1].limits")).Should(Equal(value.Position{Line: 13, Column: 2}))
+	})
+	Specify("positions in ConvTo errors", func() {
+		var y struct {
+			Servers []struct {
+				Name string
+				Port int
+			}
+		}
+		v, err := FromTOML(data)
+		Expect(err).Should(BeNil())
+		err = v.ConvTo(&y)
+		var e *value.ErrPath
+		Expect(errors.As(err, &e)).Should(BeTrue())
+		Expect(e.Path).Should(Equal("servers[1].port"))
+		Expect(e.Pos).Should(Equal(&value.Position{Line: 10, Column: 1}))
+	})
+	Specify("failed", func() {
+		_, err := FromTOML([]byte("a = "))
+		Expect(err).ShouldNot(BeNil())
+	})
+})
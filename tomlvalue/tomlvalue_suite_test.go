@@ -0,0 +1,13 @@
+package tomlvalue
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTOMLValue(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TOMLValue Suite")
+}
@@ -0,0 +1,149 @@
+package value
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// regexps caches the compiled regexp of tag option, or ErrInvalid of the invalid one.
+var regexps sync.Map
+
+// compileRegexp returns the compiled expr, which is compiled once and cached.
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if c, ok := regexps.Load(expr); ok {
+		if err, ok := c.(error); ok {
+			return nil, err
+		}
+		return c.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		regexps.Store(expr, &ErrInvalid{"Value.ConvTo", "regexp syntax", expr})
+	} else {
+		regexps.Store(expr, re)
+	}
+	return compileRegexp(expr)
+}
+
+// validateAt validates dst of field, which is at key of current path.
+func (s *convState) validateAt(key string, field reflect.StructField, dst reflect.Value) {
+	s.push(key, false)
+	defer s.pop()
+
+	if err := s.validate(field, dst); err != nil {
+		s.fail(err)
+	}
+}
+
+// validate checks dst with the rules of field's tag options, and returns ErrInvalid
+// of the first violated rule. The rules are:
+//
+//	min=n, max=n   the number, or the length of string, array, slice and map,
+//	               the bound of number is converted to dst's type, like "1s" for time.Duration
+//	oneof=a b c    the string of value is one of the space-separated values
+//	regexp=expr    the string of value matches expr, quote it like regexp='^a,b$' if it contains commas
+//
+// The nil pointer is not checked.
+func (s *convState) validate(field reflect.StructField, dst reflect.Value) error {
+	_, opts := parseTag(fieldTag(field))
+	rv := indirect(dst)
+	if !rv.IsValid() {
+		return nil
+	}
+
+	for _, rule := range []string{"min", "max"} {
+		b, ok := opts.Get(rule)
+		if !ok {
+			continue
+		}
+		m, ok := measure(rv)
+		if !ok {
+			return &ErrUnsupportedKind{"Value.validate", rv.Kind()}
+		}
+		bound, err := s.measureBound(rv, b)
+		if err != nil {
+			return err
+		}
+		if (rule == "min" && m < bound) || (rule == "max" && m > bound) {
+			return &ErrInvalid{"Value.ConvTo", rule + "=" + b, valueOf(rv)}
+		}
+	}
+
+	if oneof, ok := opts.Get("oneof"); ok {
+		str, err := (&Value{rv: rv}).String()
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, one := range strings.Fields(oneof) {
+			if str == one {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ErrInvalid{"Value.ConvTo", "oneof=" + oneof, valueOf(rv)}
+		}
+	}
+
+	if expr, ok := opts.Get("regexp"); ok {
+		re, err := compileRegexp(expr)
+		if err != nil {
+			return err
+		}
+		str, err := (&Value{rv: rv}).String()
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(str) {
+			return &ErrInvalid{"Value.ConvTo", "regexp=" + expr, valueOf(rv)}
+		}
+	}
+	return nil
+}
+
+// measure returns the number of rv to compare with min and max.
+func measure(rv reflect.Value) (float64, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(rv.String())), true
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return float64(rv.Len()), true
+	default:
+		return 0, false
+	}
+}
+
+// measureBound returns the number of bound b for rv, which is converted with the converter of s.
+func (s *convState) measureBound(rv reflect.Value, b string) (float64, error) {
+	switch rv.Kind() {
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		return strconv.ParseFloat(b, 64)
+	}
+
+	bound := reflect.New(rv.Type())
+	if err := New(b).ConvToWithOptions(bound.Interface(), ConvOptions{Converter: s.conv}); err != nil {
+		return 0, err
+	}
+	m, _ := measure(bound.Elem())
+	return m, nil
+}
+
+// valueOf returns the interface of rv, or nil if it can't be interfaced.
+func valueOf(rv reflect.Value) interface{} {
+	if !rv.CanInterface() {
+		return nil
+	}
+	return rv.Interface()
+}
@@ -0,0 +1,115 @@
+package value
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	type server struct {
+		Host    string        `value:"host,required,regexp='^[a-z]{1,8}$'"`
+		Port    int           `value:"port,required,min=1,max=65535"`
+		Level   string        `value:"level,oneof=debug info,default=info"`
+		Timeout time.Duration `value:"timeout,min=1s,max=1m"`
+		Tags    []string      `value:"tags,max=2"`
+		Weight  *float64      `value:"weight,min=0.5"`
+	}
+	type config struct {
+		Servers []server `value:"servers,required,min=1"`
+	}
+
+	errPaths := func(err error) map[string]error {
+		paths := map[string]error{}
+		for _, e := range err.(*ErrMulti).Errs {
+			paths[e.(*ErrPath).Path] = e.(*ErrPath).Err
+		}
+		return paths
+	}
+
+	Specify("valid values", func() {
+		x := map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"host": "a", "port": 80, "timeout": "10s", "tags": []string{"x"}, "weight": 1},
+			},
+		}
+		var y config
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.Servers[0].Level).Should(Equal("info"))
+	})
+	Specify("invalid values", func() {
+		x := map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"host": "a,b", "port": 0, "level": "warn", "timeout": "2m", "tags": []string{"x", "y", "z"}, "weight": 0.1},
+				map[string]interface{}{},
+			},
+		}
+		var y config
+		paths := errPaths(New(x).ConvTo(&y))
+		Expect(paths).Should(HaveLen(8))
+		Expect(paths["servers[0].host"]).Should(Equal(&ErrInvalid{"Value.ConvTo", "regexp=^[a-z]{1,8}$", "a,b"}))
+		Expect(paths["servers[0].port"]).Should(Equal(&ErrInvalid{"Value.ConvTo", "min=1", 0}))
+		Expect(paths["servers[0].level"]).Should(Equal(&ErrInvalid{"Value.ConvTo", "oneof=debug info", "warn"}))
+		Expect(paths["servers[0].timeout"]).Should(Equal(&ErrInvalid{"Value.ConvTo", "max=1m", 2 * time.Minute}))
+		Expect(paths["servers[0].tags"]).Should(BeAssignableToTypeOf((*ErrInvalid)(nil)))
+		Expect(paths["servers[0].weight"]).Should(BeAssignableToTypeOf((*ErrInvalid)(nil)))
+		Expect(paths["servers[1].host"]).Should(Equal(&ErrNotExist{"Value.ConvTo", "host key"}))
+		Expect(paths["servers[1].port"]).Should(Equal(&ErrNotExist{"Value.ConvTo", "port key"}))
+	})
+	Specify("required missing", func() {
+		var y config
+		paths := errPaths(New(map[string]interface{}{}).ConvTo(&y))
+		Expect(paths).Should(Equal(map[string]error{
+			"servers": &ErrNotExist{"Value.ConvTo", "servers key"},
+		}))
+
+		paths = errPaths(New(map[string]interface{}{"servers": []interface{}{}}).ConvTo(&y))
+		Expect(paths["servers"]).Should(BeAssignableToTypeOf((*ErrInvalid)(nil)))
+	})
+	Specify("defaulted values", func() {
+		var y struct {
+			Level string `value:"level,oneof=a b,default=c"`
+			Name  string `value:"name,regexp='^[a-z]+,[a-z]+$',default=a"`
+		}
+		paths := errPaths(New(map[string]interface{}{}).ConvTo(&y))
+		Expect(paths).Should(Equal(map[string]error{
+			"level": &ErrInvalid{"Value.ConvTo", "oneof=a b", "c"},
+			"name":  &ErrInvalid{"Value.ConvTo", "regexp=^[a-z]+,[a-z]+$", "a"},
+		}))
+		Expect(New(map[string]interface{}{"level": "a", "name": "a,b"}).ConvTo(&y)).Should(BeNil())
+	})
+	Specify("bound with converter", func() {
+		type level int
+		conv := NewConverter()
+		conv.Register(reflect.TypeOf(level(0)), func(v *Value, dst reflect.Value) error {
+			s, err := v.String()
+			if err != nil {
+				return err
+			}
+			dst.SetInt(int64(strings.Index("debug info warn", s)))
+			return nil
+		})
+		var y struct {
+			Level level `value:"level,min=info"`
+		}
+		opts := ConvOptions{Converter: conv}
+		Expect(New(map[string]interface{}{"level": "warn"}).ConvToWithOptions(&y, opts)).Should(BeNil())
+		paths := errPaths(New(map[string]interface{}{"level": "debug"}).ConvToWithOptions(&y, opts))
+		Expect(paths["level"]).Should(Equal(&ErrInvalid{"Value.ConvTo", "min=info", level(0)}))
+	})
+	Specify("invalid regexp", func() {
+		var y struct {
+			Name string `value:"name,regexp=[a-"`
+		}
+		for i := 0; i < 2; i++ {
+			paths := errPaths(New(map[string]interface{}{"name": "a"}).ConvTo(&y))
+			Expect(paths["name"]).Should(Equal(&ErrInvalid{"Value.ConvTo", "regexp syntax", "[a-"}))
+		}
+		re, err := compileRegexp("^a$")
+		Expect(err).Should(BeNil())
+		Expect(compileRegexp("^a$")).Should(BeIdenticalTo(re))
+	})
+})
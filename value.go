@@ -3,15 +3,19 @@
 package value
 
 import (
+	"encoding"
 	"fmt"
-	"math/bits"
 	"reflect"
+	"time"
 )
 
 // Value ...
 type Value struct {
 	iv interface{}
 	rv reflect.Value
+
+	pos Positions
+	at  []pathSeg // path of t in pos
 }
 
 // New new a Value from v
@@ -106,6 +110,45 @@ func (v *Value) Put(key, val interface{}) (err error) {
 	}
 }
 
+// Delete deletes key from map, array, slice or struct(structed type).
+//
+// If t's kind is map, the k indicates key of map, and it's deleted.
+// If t's kind is slice, the k indicates index of slice, the element is removed
+// and the following elements are shifted in place if the slice is settable, like via pointer,
+// otherwise they're copied to a new slice, that the original one is unchanged.
+// If t's kind is array/struct, the k indicates index of array/fieldname of struct,
+// which is set to zero value.
+// The pointer is followed as Value.Put.
+//
+// If k not in t, returns ErrNotExist.
+// If t's kind is not map, array, slice or struct, returns ErrUnsupportedKind.
+func (v *Value) Delete(key interface{}) error {
+	rv := v.getrv()
+
+	switch rv.Kind() {
+	case reflect.Map:
+		return v.mapDelete(key)
+	case reflect.Slice:
+		return v.sliceDelete(key)
+	case reflect.Ptr:
+		rvv := indirect(rv)
+		switch rvv.Kind() {
+		case reflect.Map:
+			return (&Value{rv: rvv}).mapDelete(key)
+		case reflect.Slice:
+			return (&Value{rv: rvv}).sliceDelete(key)
+		case reflect.Array:
+			return (&Value{rv: rvv}).arrayDelete(key)
+		case reflect.Struct:
+			return (&Value{rv: rvv}).structDelete(key)
+		default:
+			return &ErrUnsupportedKind{"Value.Delete", v.getrv().Kind()}
+		}
+	default:
+		return &ErrUnsupportedKind{"Value.Delete", v.getrv().Kind()}
+	}
+}
+
 // Bytes returns t's underlying value as a []bytes.
 // It returns error if t's underlying value is not a slice of bytes.
 func (v *Value) Bytes() ([]byte, error) {
@@ -138,233 +181,95 @@ func (v *Value) Bool() (bool, error) {
 }
 
 // Int returns t's underlying value as an int.
-// It returns error if t's kind is not Int, Int8, Int16, Int32, Uint8 or Uint16,
-// and if t's kind is Int64 or Uint32 also Int is 32 bits.
-func (v *Value) Int() (i int, err error) {
-	switch v.getrv().Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
-		i = int(v.int())
-	case reflect.Int64:
-		if bits.UintSize == 64 { // if int size is 64
-			i = int(v.int())
-		} else { // if int size is 32
-			err = &ErrUnsupportedKind{"Value.Int", v.getrv().Kind()}
-		}
-
-	case reflect.Uint8, reflect.Uint16:
-		i = int(v.uint())
-
-	case reflect.Uint32:
-		if bits.UintSize == 64 {
-			i = int(v.uint())
-		} else {
-			err = &ErrUnsupportedKind{"Value.Int", v.getrv().Kind()}
-		}
-
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Int()
-
-	default:
-		err = &ErrUnsupportedKind{"Value.Int", v.getrv().Kind()}
-	}
-	return
+// The t's value can be any integer, float or numeric string,
+// it returns ErrNumOverflow if the value overflows int,
+// ErrTruncated if the value is a non-integral float.
+func (v *Value) Int() (int, error) {
+	i, err := v.toInt("Value.Int", reflect.Int)
+	return int(i), err
 }
 
 // Int8 returns t's underlying value as an int8.
-// It returns error if t's kind is not Int8.
+// It returns error if t's value can't be represented as an int8, see Int.
 func (v *Value) Int8() (int8, error) {
-	switch v.getrv().Kind() {
-	case reflect.Int8:
-		return int8(v.int()), nil
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Int8()
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Int8", v.getrv().Kind()}
-	}
+	i, err := v.toInt("Value.Int8", reflect.Int8)
+	return int8(i), err
 }
 
 // Int16 returns t's underlying value as an int16.
-// It returns error if t's kind is not Int, Int8, Int16, or Uint8.
+// It returns error if t's value can't be represented as an int16, see Int.
 func (v *Value) Int16() (int16, error) {
-	switch v.getrv().Kind() {
-	case reflect.Int8, reflect.Int16:
-		return int16(v.int()), nil
-	case reflect.Uint8:
-		return int16(v.uint()), nil
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Int16()
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Int16", v.getrv().Kind()}
-	}
+	i, err := v.toInt("Value.Int16", reflect.Int16)
+	return int16(i), err
 }
 
 // Int32 returns t's underlying value as an int32.
-// It returns error if t's kind is not Int, Int8, Int16, Int32, Uint8 or Uint16,
-// and if t's kind is Int also Int is 64 bits.
+// It returns error if t's value can't be represented as an int32, see Int.
 func (v *Value) Int32() (int32, error) {
-	switch v.getrv().Kind() {
-	case reflect.Int8, reflect.Int16, reflect.Int32:
-		return int32(v.int()), nil
-	case reflect.Int:
-		if bits.UintSize == 32 { // 32
-			return int32(v.int()), nil
-		}
-		return 0, &ErrUnsupportedKind{"Value.Int32", v.getrv().Kind()}
-
-	case reflect.Uint8, reflect.Uint16:
-		return int32(v.uint()), nil
-
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Int32()
-
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Int32", v.getrv().Kind()}
-	}
+	i, err := v.toInt("Value.Int32", reflect.Int32)
+	return int32(i), err
 }
 
 // Int64 returns t's underlying value as an int64.
-// It returns error if t's kind is not Int, Int8, Int16, Int32, Uint8, Uint16, Uint32
-// and if t's kind is Uint also Uint is 64 bits.
+// It returns error if t's value can't be represented as an int64, see Int.
 func (v *Value) Int64() (int64, error) {
-	switch v.getrv().Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return v.int(), nil
-
-	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
-		return int64(v.uint()), nil
-	case reflect.Uint:
-		if bits.UintSize == 32 { // 32
-			return int64(v.uint()), nil
-		}
-		return 0, &ErrUnsupportedKind{"Value.Int64", v.getrv().Kind()}
-
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Int64()
-
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Int64", v.getrv().Kind()}
-	}
+	return v.toInt("Value.Int64", reflect.Int64)
 }
 
 // Uint returns t's underlying value as an uint.
-// It returns error if t's kind is not Uint, Uint8, Uint16 or Uint32,
-// and if t's kind is Uint64 also Uint is 32 bits.
-func (v *Value) Uint() (i uint, err error) {
-	switch v.getrv().Kind() {
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
-		i = uint(v.uint())
-	case reflect.Uint64:
-		if bits.UintSize == 64 { // 64
-			i = uint(v.uint())
-		} else { // 32
-			err = &ErrUnsupportedKind{"Value.Uint", v.getrv().Kind()}
-		}
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Uint()
-
-	default:
-		err = &ErrUnsupportedKind{"Value.Uint", v.getrv().Kind()}
-	}
-	return
+// The t's value can be any integer, float or numeric string,
+// it returns ErrNumOverflow if the value is negative or overflows uint,
+// ErrTruncated if the value is a non-integral float.
+func (v *Value) Uint() (uint, error) {
+	u, err := v.toUint("Value.Uint", reflect.Uint)
+	return uint(u), err
 }
 
 // Uint8 returns t's underlying value as an uint8.
-// It returns error if t's kind is not Uint8.
+// It returns error if t's value can't be represented as an uint8, see Uint.
 func (v *Value) Uint8() (uint8, error) {
-	switch v.getrv().Kind() {
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Uint8()
-	case reflect.Uint8:
-		return uint8(v.uint()), nil
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Uint8", v.getrv().Kind()}
-	}
+	u, err := v.toUint("Value.Uint8", reflect.Uint8)
+	return uint8(u), err
 }
 
 // Uint16 returns t's underlying value as an uint16.
-// It returns error if t's kind is not Uint8 or Uint16.
+// It returns error if t's value can't be represented as an uint16, see Uint.
 func (v *Value) Uint16() (uint16, error) {
-	switch v.getrv().Kind() {
-	case reflect.Uint8, reflect.Uint16:
-		return uint16(v.uint()), nil
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Uint16()
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Uint16", v.getrv().Kind()}
-	}
+	u, err := v.toUint("Value.Uint16", reflect.Uint16)
+	return uint16(u), err
 }
 
 // Uint32 returns t's underlying value as an uint32.
-// It returns error if t's kind is not Uint8, Uint16 or Uint32,
-// and if t's kind is Uint also Uint is 64 bits.
+// It returns error if t's value can't be represented as an uint32, see Uint.
 func (v *Value) Uint32() (uint32, error) {
-	switch v.getrv().Kind() {
-	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
-		return uint32(v.uint()), nil
-
-	case reflect.Uint:
-		if bits.UintSize == 32 { // 32
-			return uint32(v.uint()), nil
-		}
-		return 0, &ErrUnsupportedKind{"Value.Uint32", v.getrv().Kind()}
-
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Uint32()
-
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Uint32", v.getrv().Kind()}
-	}
+	u, err := v.toUint("Value.Uint32", reflect.Uint32)
+	return uint32(u), err
 }
 
 // Uint64 returns t's underlying value as an uint64.
-// It returns error if t's kind is not Uint*.
+// It returns error if t's value can't be represented as an uint64, see Uint.
 func (v *Value) Uint64() (uint64, error) {
-	switch v.getrv().Kind() {
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return v.uint(), nil
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Uint64()
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Uint64", v.getrv().Kind()}
-	}
+	return v.toUint("Value.Uint64", reflect.Uint64)
 }
 
 // Float32 returns t's underlying value as an float32.
-// It returns error if t's kind is not Uint*, Int* or Float32.
+// The t's value can be any integer, float or numeric string,
+// it returns ErrNumOverflow if the value is out of the range of float32,
+// ErrTruncated if the integer can't be represented exactly.
 func (v *Value) Float32() (float32, error) {
-	switch v.getrv().Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return float32(v.int()), nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return float32(v.uint()), nil
-	case reflect.Float32:
-		return float32(v.float()), nil
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Float32()
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Float32", v.getrv().Kind()}
-	}
+	f, err := v.toFloat("Value.Float32", reflect.Float32)
+	return float32(f), err
 }
 
 // Float64 returns t's underlying value as an float64.
-// It returns error if t's kind is not Uint*, Int* or Float*.
+// The t's value can be any integer, float or numeric string,
+// it returns ErrTruncated if the integer can't be represented exactly.
 func (v *Value) Float64() (float64, error) {
-	switch v.getrv().Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return float64(v.int()), nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return float64(v.uint()), nil
-	case reflect.Float32, reflect.Float64:
-		return v.float(), nil
-	case reflect.Interface, reflect.Ptr:
-		return (&Value{rv: indirect(v.getrv())}).Float64()
-	default:
-		return 0, &ErrUnsupportedKind{"Value.Float64", v.getrv().Kind()}
-	}
+	return v.toFloat("Value.Float64", reflect.Float64)
 }
 
 // Complex64 returns t's underlying value as an complex64.
-// It returns error if t's kind is not Uint*, Int*, Float32 or Complex64.
+// It returns error if t's kind is not Uint*, Int*, Float32, Complex64 or complex string like "(1+2i)".
 func (v *Value) Complex64() (complex64, error) {
 	switch v.getrv().Kind() {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -377,13 +282,19 @@ func (v *Value) Complex64() (complex64, error) {
 		return complex64(v.complex_()), nil
 	case reflect.Interface, reflect.Ptr:
 		return (&Value{rv: indirect(v.getrv())}).Complex64()
+	case reflect.String:
+		c, err := parseComplex(v.string(), 64)
+		if err != nil {
+			return 0i, &ErrUnsupportedKind{"Value.Complex64", "non-complex string"}
+		}
+		return complex64(c), nil
 	default:
 		return 0i, &ErrUnsupportedKind{"Value.Complex64", v.getrv().Kind()}
 	}
 }
 
 // Complex128 returns t's underlying value as an complex128.
-// It returns error if t's kind is not Uint*, Int*, Float* or Complex*.
+// It returns error if t's kind is not Uint*, Int*, Float*, Complex* or complex string like "(1+2i)".
 func (v *Value) Complex128() (complex128, error) {
 	switch v.getrv().Kind() {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -396,6 +307,12 @@ func (v *Value) Complex128() (complex128, error) {
 		return v.complex_(), nil
 	case reflect.Interface, reflect.Ptr:
 		return (&Value{rv: indirect(v.getrv())}).Complex128()
+	case reflect.String:
+		c, err := parseComplex(v.string(), 128)
+		if err != nil {
+			return 0i, &ErrUnsupportedKind{"Value.Complex128", "non-complex string"}
+		}
+		return c, nil
 	default:
 		return 0i, &ErrUnsupportedKind{"Value.Complex128", v.getrv().Kind()}
 	}
@@ -475,12 +392,33 @@ func (v *Value) Ptr() uintptr {
 	return v.getrv().Pointer()
 }
 
+// String returns t's underlying value as a string, that can be converted back by ConvTo.
+// The time.Time is formatted with TimeLayout,
+// otherwise the fmt.Stringer is preferred, and then the encoding.TextMarshaler.
+// The floats are formatted with the fewest digits to represent it, the bytes are kept as is,
+// and the map, struct, array and slice are formatted as JSON, in which the bytes are base64.
+// It returns ErrCyclic if the composite is cyclic.
 func (v *Value) String() (string, error) {
 	iv := v.getiv()
+	switch t := iv.(type) {
+	case time.Time:
+		return t.Format(TimeLayout), nil
+	case *time.Time:
+		if t != nil {
+			return t.Format(TimeLayout), nil
+		}
+	}
 	strger, ok := iv.(fmt.Stringer)
 	if ok {
 		return strger.String(), nil
 	}
+	if m, ok := iv.(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(text), nil
+	}
 
 	switch v.getrv().Kind() {
 	case reflect.Invalid:
@@ -494,17 +432,20 @@ func (v *Value) String() (string, error) {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		return fmt.Sprintf("%d", v.uint()), nil
 	case reflect.Float32, reflect.Float64:
-		return fmt.Sprintf("%g", v.float()), nil
+		return formatFloat(v.float(), v.getrv().Type().Bits()), nil
 	case reflect.Complex64, reflect.Complex128:
-		return fmt.Sprintf("%g", v.complex_()), nil
+		return formatComplex(v.complex_(), v.getrv().Type().Bits()), nil
 	case reflect.Chan:
 		return fmt.Sprintf("%p", v.getrv().Interface()), nil
 	case reflect.UnsafePointer:
 		return fmt.Sprintf("%x", v.getrv().Interface()), nil
-	case reflect.Struct:
-		return fmt.Sprintf("%#v", v.getrv().Interface()), nil
-	case reflect.Slice, reflect.Array, reflect.Map:
-		return fmt.Sprintf("%v", v.getrv().Interface()), nil
+	case reflect.Slice, reflect.Array:
+		if v.getrv().Type().Elem().Kind() == reflect.Uint8 {
+			return string(bytesOf(v.getrv())), nil
+		}
+		return formatComposite(v.getrv())
+	case reflect.Struct, reflect.Map:
+		return formatComposite(v.getrv())
 	case reflect.Interface, reflect.Ptr:
 		return (&Value{rv: indirect(v.getrv())}).String()
 	default:
@@ -1,12 +1,15 @@
 package value
 
 import (
-	"math/bits"
+	"errors"
+	"fmt"
+	"math/big"
 	"net"
 	"net/mail"
 	"net/url"
 	"reflect"
 	"regexp"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -67,11 +70,7 @@ var _ = Describe("Gets", func() {
 			}
 
 			ut64 := New(uint64(x))
-			if bits.UintSize == 64 {
-				Expect(ut64.Uint()).To(Equal(x))
-			} else {
-				ExpectErr(ut64.Uint()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
-			}
+			Expect(ut64.Uint()).To(Equal(x))
 		})
 		Specify("from ptr kind", func() {
 			x := uint(1)
@@ -137,11 +136,7 @@ var _ = Describe("Gets", func() {
 			}
 
 			uv := New(uint(x))
-			if bits.UintSize == 32 {
-				Expect(uv.Uint32()).To(Equal(x))
-			} else {
-				ExpectErr(uv.Uint32()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
-			}
+			Expect(uv.Uint32()).To(Equal(x))
 		})
 		Specify("from ptr kind", func() {
 			x := uint32(12)
@@ -192,19 +187,8 @@ var _ = Describe("Gets", func() {
 				Expect(v.Int()).To(Equal(x))
 			}
 
-			t64 := New(int64(x))
-			if bits.UintSize == 64 {
-				Expect(t64.Int()).To(Equal(x))
-			} else {
-				ExpectErr(t64.Int()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
-			}
-
-			ut32 := New(uint32(x))
-			if bits.UintSize == 64 {
-				Expect(ut32.Int()).To(Equal(x))
-			} else {
-				ExpectErr(ut32.Int()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
-			}
+			Expect(New(int64(x)).Int()).To(Equal(x))
+			Expect(New(uint32(x)).Int()).To(Equal(x))
 		})
 		Specify("from ptr kind", func() {
 			x := int(12)
@@ -268,11 +252,7 @@ var _ = Describe("Gets", func() {
 			}
 
 			v := New(int(x))
-			if bits.UintSize == 32 {
-				Expect(v.Int32()).To(Equal(x))
-			} else {
-				ExpectErr(v.Int32()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
-			}
+			Expect(v.Int32()).To(Equal(x))
 		})
 		Specify("from ptr kind", func() {
 			x := int32(12)
@@ -302,11 +282,7 @@ var _ = Describe("Gets", func() {
 			}
 
 			uv := New(uint(x))
-			if bits.UintSize == 32 {
-				Expect(uv.Int64()).To(Equal(x))
-			} else {
-				ExpectErr(uv.Int64()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
-			}
+			Expect(uv.Int64()).To(Equal(x))
 		})
 		Specify("from ptr kind", func() {
 			x := int64(12)
@@ -318,6 +294,52 @@ var _ = Describe("Gets", func() {
 			ExpectErr(v.Int64()).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
 		})
 	})
+	Context("with cross kind numeric", func() {
+		Specify("from other integer kind", func() {
+			Expect(New(int(12)).Int16()).To(Equal(int16(12)))
+			Expect(New(uint64(12)).Int()).To(Equal(12))
+			Expect(New(int(12)).Uint8()).To(Equal(uint8(12)))
+			Expect(New(int64(-12)).Int8()).To(Equal(int8(-12)))
+		})
+		Specify("from float kind", func() {
+			Expect(New(float64(12)).Int()).To(Equal(12))
+			Expect(New(float32(12)).Uint16()).To(Equal(uint16(12)))
+			Expect(New(float64(-128)).Int8()).To(Equal(int8(-128)))
+		})
+		Specify("from numeric string", func() {
+			Expect(New("12").Int32()).To(Equal(int32(12)))
+			Expect(New("18446744073709551615").Uint64()).To(Equal(uint64(18446744073709551615)))
+			Expect(New("12.0").Uint()).To(Equal(uint(12)))
+			Expect(New("1.5").Float32()).To(Equal(float32(1.5)))
+		})
+		Specify("overflow", func() {
+			ExpectErr(New(int(128)).Int8()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+			ExpectErr(New(int(-1)).Uint()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+			ExpectErr(New(uint64(1 << 63)).Int64()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+			ExpectErr(New(uint16(256)).Uint8()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+			ExpectErr(New(float64(1e20)).Int64()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+			ExpectErr(New(float64(1e39)).Float32()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+			ExpectErr(New("-1").Uint32()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+
+			_, err := New(int(300)).Int8()
+			Expect(err).To(Equal(&ErrNumOverflow{"Value.Int8", reflect.Int8, int64(300)}))
+			_, err = New(uint16(256)).Uint8()
+			Expect(err).To(Equal(&ErrNumOverflow{"Value.Uint8", reflect.Uint8, uint64(256)}))
+			_, err = New(float64(1e39)).Float32()
+			Expect(err).To(Equal(&ErrNumOverflow{"Value.Float32", reflect.Float32, 1e39}))
+			_, err = New("-1").Uint32()
+			Expect(err).To(Equal(&ErrNumOverflow{"Value.Uint32", reflect.Uint32, int64(-1)}))
+		})
+		Specify("truncated", func() {
+			ExpectErr(New(1.5).Int()).To(BeAssignableToTypeOf((*ErrTruncated)(nil)))
+			ExpectErr(New("-0.5").Uint()).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+			ExpectErr(New("2.5").Uint()).To(BeAssignableToTypeOf((*ErrTruncated)(nil)))
+			ExpectErr(New(int64(1<<53 + 1)).Float64()).To(BeAssignableToTypeOf((*ErrTruncated)(nil)))
+			ExpectErr(New(uint64(1<<64 - 1)).Float64()).To(BeAssignableToTypeOf((*ErrTruncated)(nil)))
+			ExpectErr(New(int32(1<<24 + 1)).Float32()).To(BeAssignableToTypeOf((*ErrTruncated)(nil)))
+			Expect(New(int64(1 << 62)).Float64()).To(Equal(float64(1 << 62)))
+		})
+	})
 	Context("with Float32()", func() {
 		Specify("from int*, uint*, float32 kind", func() {
 			x := float32(12)
@@ -768,6 +790,14 @@ var _ = Describe("Gets", func() {
 			v := New(&x)
 			Expect(v.String()).Should(Equal(x.String()))
 		})
+		Specify("text marshaler type", func() {
+			x := TextMarshalerTest{"a", "b"}
+			Expect(New(x).String()).Should(Equal("a:b"))
+			Expect(New(&x).String()).Should(Equal("a:b"))
+		})
+		Specify("nil value", func() {
+			Expect(New(nil).String()).Should(Equal(""))
+		})
 	})
 })
 
@@ -777,6 +807,48 @@ func (s *StringerTest) String() string {
 	return "test"
 }
 
+type TextMarshalerTest struct {
+	A, B string
+}
+
+func (t TextMarshalerTest) MarshalText() ([]byte, error) {
+	return []byte(t.A + ":" + t.B), nil
+}
+
+func (t *TextMarshalerTest) UnmarshalText(text []byte) error {
+	i := strings.IndexByte(string(text), ':')
+	if i < 0 {
+		return fmt.Errorf("invalid text %q", text)
+	}
+	t.A, t.B = string(text[:i]), string(text[i+1:])
+	return nil
+}
+
+type UpperTextTest string
+
+func (u *UpperTextTest) UnmarshalText(text []byte) error {
+	*u = UpperTextTest(strings.ToUpper(string(text)))
+	return nil
+}
+
+type BinaryUnmarshalerTest struct {
+	Len int
+}
+
+func (b *BinaryUnmarshalerTest) UnmarshalBinary(data []byte) error {
+	b.Len = len(data)
+	return nil
+}
+
+type JSONUnmarshalerTest struct {
+	Raw string
+}
+
+func (j *JSONUnmarshalerTest) UnmarshalJSON(data []byte) error {
+	j.Raw = string(data)
+	return nil
+}
+
 var _ = Describe("Sets", func() {
 	Context("with Set()", func() {
 		Specify("int kind", func() {
@@ -865,6 +937,61 @@ var _ = Describe("Sets", func() {
 			Expect(vx.Put("nil", "nil")).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
 		})
 	})
+
+	Context("with Delete()", func() {
+		Specify("from map kind", func() {
+			x := map[string]interface{}{
+				"A": 1,
+				"B": "b",
+			}
+
+			vx := New(x)
+			Expect(vx.Delete("A")).Should(BeNil())
+			Expect(x).Should(Equal(map[string]interface{}{"B": "b"}))
+			Expect(vx.Delete("A")).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+			Expect(vx.Delete(1)).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+		})
+		Specify("from slice kind", func() {
+			x := []interface{}{1, "b", 1.2}
+
+			vx := New(x)
+			Expect(vx.Delete(0)).Should(BeNil())
+			Expect(vx.Interface()).Should(Equal([]interface{}{"b", 1.2}))
+			Expect(x).Should(Equal([]interface{}{1, "b", 1.2}))
+			Expect(vx.Delete(2)).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+			Expect(vx.Delete("0")).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+
+			y := []interface{}{1, "b", 1.2}
+			vx = New(&y)
+			Expect(vx.Delete(1)).Should(BeNil())
+			Expect(y).Should(Equal([]interface{}{1, 1.2}))
+		})
+		Specify("from array kind", func() {
+			x := [3]interface{}{1, "b"}
+
+			vx := New(&x)
+			Expect(vx.Delete(0)).Should(BeNil())
+			Expect(x).Should(Equal([3]interface{}{nil, "b"}))
+			Expect(vx.Delete(3)).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+		})
+		Specify("from struct kind", func() {
+			x := struct {
+				A int
+				B string
+				c string
+			}{1, "b", "c"}
+
+			vx := New(&x)
+			Expect(vx.Delete("A")).Should(BeNil())
+			Expect(x.A).Should(Equal(0))
+			Expect(vx.Delete("D")).To(BeAssignableToTypeOf((*ErrNotExist)(nil)))
+			Expect(vx.Delete("c")).To(BeAssignableToTypeOf((*ErrCannotSet)(nil)))
+		})
+		Specify("from other kind", func() {
+			vx := New("a")
+			Expect(vx.Delete("nil")).To(BeAssignableToTypeOf((*ErrUnsupportedKind)(nil)))
+		})
+	})
 })
 
 var _ = Describe("Dos", func() {
@@ -910,6 +1037,17 @@ var _ = Describe("ConvTo", func() {
 		Expect(err).Should(BeNil())
 		Expect(y).Should(Equal(x))
 	})
+	Specify("int kind from other numeric kind", func() {
+		var y int8
+		Expect(New(float64(12)).ConvTo(&y)).Should(BeNil())
+		Expect(y).Should(Equal(int8(12)))
+		Expect(New(uint64(12)).ConvTo(&y)).Should(BeNil())
+		Expect(y).Should(Equal(int8(12)))
+
+		Expect(New(300).ConvTo(&y)).To(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+		Expect(New(300).ConvTo(&y).(*ErrNumOverflow).Value).To(Equal(int64(300)))
+		Expect(New(1.5).ConvTo(&y)).To(BeAssignableToTypeOf((*ErrTruncated)(nil)))
+	})
 	Specify("uint kind", func() {
 		x := uint(123)
 		y := uint(0)
@@ -1088,6 +1226,40 @@ var _ = Describe("ConvTo", func() {
 		Expect(y).Should(Equal(ByteSize(10 * 1024)))
 	})
 
+	Specify("text unmarshaler type", func() {
+		var y struct {
+			A TextMarshalerTest
+			B *big.Int
+			C UpperTextTest
+			D TextMarshalerTest
+		}
+		x := map[string]interface{}{
+			"a": "a:b",
+			"b": "12345678901234567890",
+			"c": "abc",
+			"d": []byte("c:d"),
+		}
+		Expect(New(x).ConvTo(&y)).Should(BeNil())
+		Expect(y.A).Should(Equal(TextMarshalerTest{"a", "b"}))
+		Expect(y.B.String()).Should(Equal("12345678901234567890"))
+		Expect(y.C).Should(Equal(UpperTextTest("ABC")))
+		Expect(y.D).Should(Equal(TextMarshalerTest{"c", "d"}))
+
+		Expect(New("ab").ConvTo(&y.A)).ShouldNot(BeNil())
+	})
+
+	Specify("binary unmarshaler type", func() {
+		var y BinaryUnmarshalerTest
+		Expect(New([]byte("abc")).ConvTo(&y)).Should(BeNil())
+		Expect(y.Len).Should(Equal(3))
+	})
+
+	Specify("json unmarshaler type", func() {
+		var y JSONUnmarshalerTest
+		Expect(New(map[string]int{"a": 1}).ConvTo(&y)).Should(BeNil())
+		Expect(y.Raw).Should(Equal(`{"a":1}`))
+	})
+
 	Specify("nest struct kind", func() {
 		type xx struct {
 			X int
@@ -1115,6 +1287,84 @@ var _ = Describe("ConvTo", func() {
 		Expect(y.C.X).Should(Equal(10))
 		Expect(y.C.Y).Should(Equal(11))
 	})
+	Specify("default values", func() {
+		type server struct {
+			Host    string        `value:"host,default=localhost,regexp=^[a-z]+$"`
+			Timeout time.Duration `value:"timeout,default=30s"`
+		}
+		var y struct {
+			Name    string   `default:"a"`
+			Port    *int     `value:"port,default=80,min=1"`
+			Tags    []string `value:"tags,default='a, b,c'"`
+			Quote   string   `value:"quote,default='it''s'"`
+			Server  server
+			Servers []server
+			Empty   []int `default:""`
+			None    int
+		}
+		x := map[string]interface{}{
+			"name":    "x",
+			"servers": []interface{}{map[string]interface{}{"host": "h"}},
+		}
+
+		var meta Metadata
+		Expect(New(x).ConvToWithOptions(&y, ConvOptions{Metadata: &meta})).Should(BeNil())
+		Expect(y.Name).Should(Equal("x"))
+		Expect(*y.Port).Should(Equal(80))
+		Expect(y.Tags).Should(Equal([]string{"a", "b", "c"}))
+		Expect(y.Quote).Should(Equal("it's"))
+		Expect(y.Server).Should(Equal(server{"localhost", 30 * time.Second}))
+		Expect(y.Servers).Should(Equal([]server{{"h", 30 * time.Second}}))
+		Expect(y.Empty).Should(BeEmpty())
+		Expect(meta.Defaulted).Should(Equal([]string{
+			"Empty", "Server.host", "Server.timeout", "port", "quote", "servers[0].timeout", "tags",
+		}))
+		Expect(meta.Unset).Should(Equal([]string{"None"}))
+		Expect(meta.Keys).Should(Equal([]string{"name", "servers", "servers[0]", "servers[0].host"}))
+	})
+	Specify("invalid default value", func() {
+		var y struct {
+			Timeout time.Duration `default:"x"`
+		}
+		err := New(map[string]interface{}{}).ConvTo(&y)
+		Expect(err).To(BeAssignableToTypeOf((*ErrMulti)(nil)))
+		Expect(err.(*ErrMulti).Errs[0].(*ErrPath).Path).Should(Equal("Timeout"))
+	})
+	Specify("collect errors with path", func() {
+		type server struct {
+			Port    int
+			Timeout time.Duration
+		}
+		var y struct {
+			Name    string
+			Servers []server
+			Ports   map[string]uint8
+		}
+		x := map[string]interface{}{
+			"name": "a",
+			"servers": []interface{}{
+				map[string]interface{}{"port": 80, "timeout": "1s"},
+				map[string]interface{}{"port": 1.5, "timeout": "x"},
+			},
+			"ports": map[string]int{"a": 1, "b": -1},
+		}
+
+		err := New(x).ConvTo(&y)
+		Expect(err).To(BeAssignableToTypeOf((*ErrMulti)(nil)))
+		Expect(y.Name).Should(Equal("a"))
+		Expect(y.Servers[0]).Should(Equal(server{80, time.Second}))
+		Expect(y.Ports).Should(Equal(map[string]uint8{"a": 1}))
+
+		var paths []string
+		for _, e := range err.(*ErrMulti).Unwrap() {
+			paths = append(paths, e.(*ErrPath).Path)
+		}
+		Expect(paths).Should(Equal([]string{"ports.b", "servers[1].port", "servers[1].timeout"}))
+
+		var truncated *ErrTruncated
+		Expect(errors.As(err, &truncated)).Should(BeTrue())
+		Expect(truncated.Value).Should(Equal(1.5))
+	})
 	Specify("to chan kind", func() {
 		x := map[string]interface{}{
 			"A": 1,
@@ -1242,7 +1492,13 @@ var _ = Describe("Errs", func() {
 		m := "method"
 		k := reflect.Int
 		es := "table: call of " + m + " overflows " + k.String()
-		Expect((&ErrNumOverflow{m, k}).Error()).To(Equal(es))
+		Expect((&ErrNumOverflow{m, k, nil}).Error()).To(Equal(es))
+	})
+	Specify("of ErrNumOverflow with value", func() {
+		m := "method"
+		k := reflect.Int8
+		es := "table: call of " + m + " overflows " + k.String() + " with 300"
+		Expect((&ErrNumOverflow{m, k, 300}).Error()).To(Equal(es))
 	})
 	Specify("of ErrCannotBeNil", func() {
 		m := "method"
@@ -1260,11 +1516,11 @@ var _ = Describe("Errs", func() {
 		es := "table: call of " + m + " on unaddressable value"
 		Expect((&ErrCannotSet{m}).Error()).To(Equal(es))
 	})
-	Specify("of ErrNumOverflow", func() {
+	Specify("of ErrTruncated", func() {
 		m := "method"
 		k := reflect.Int
-		es := "table: call of " + m + " overflows " + k.String()
-		Expect((&ErrNumOverflow{m, k}).Error()).To(Equal(es))
+		es := "table: call of " + m + " truncates 1.5 to " + k.String()
+		Expect((&ErrTruncated{m, k, 1.5}).Error()).To(Equal(es))
 	})
 	Specify("of ErrTypeUnequal", func() {
 		m := "method"
@@ -1278,6 +1534,38 @@ var _ = Describe("Errs", func() {
 		es := "table: call of " + m + " out of range"
 		Expect((&ErrOutOfRange{m}).Error()).To(Equal(es))
 	})
+	Specify("of ErrCyclic", func() {
+		m := "method"
+		es := "table: call of " + m + " on cyclic value"
+		Expect((&ErrCyclic{m}).Error()).To(Equal(es))
+	})
+	Specify("of ErrUnmatched", func() {
+		m := "method"
+		es := "table: call of " + m + " with unused keys a, b and unset fields C"
+		Expect((&ErrUnmatched{m, []string{"a", "b"}, []string{"C"}}).Error()).To(Equal(es))
+	})
+	Specify("of ErrMulti", func() {
+		m := "method"
+		errs := []error{&ErrOutOfRange{"a"}, &ErrCannotSet{"b"}}
+		es := "table: call of " + m + " with 2 errors: " + errs[0].Error() + "; " + errs[1].Error()
+		Expect((&ErrMulti{m, errs}).Error()).To(Equal(es))
+		Expect((&ErrMulti{m, errs}).Unwrap()).To(Equal(errs))
+	})
+	Specify("of ErrInvalid", func() {
+		m := "method"
+		es := "table: call of " + m + " violates min=1 with 0"
+		Expect((&ErrInvalid{m, "min=1", 0}).Error()).To(Equal(es))
+	})
+	Specify("of ErrPath", func() {
+		m := "method"
+		err := &ErrOutOfRange{m}
+		es := "table: call of " + m + " at \"[1]\" of path \"a[1]\": " + err.Error()
+		Expect((&ErrPath{m, "a[1]", "[1]", err, nil}).Error()).To(Equal(es))
+		Expect((&ErrPath{m, "a[1]", "[1]", err, nil}).Unwrap()).To(Equal(err))
+
+		es = "table: call of " + m + " on path \"a\" (line 1, column 2): " + err.Error()
+		Expect((&ErrPath{m, "a", "", err, &Position{1, 2}}).Error()).To(Equal(es))
+	})
 	Specify("of ErrUnsupportedKind", func() {
 		m := "method"
 		k := reflect.Int
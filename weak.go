@@ -0,0 +1,126 @@
+package value
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// convToWeakZero sets dst to zero value if t is an empty string and dst is not a string,
+// it's prior to the registered converters and unmarshalers.
+func (v *Value) convToWeakZero(dst reflect.Value) bool {
+	src := indirect(v.getrv())
+	if src.Kind() != reflect.String || src.Len() != 0 || dst.Kind() == reflect.String || dst.Kind() == reflect.Interface {
+		return false
+	}
+	dst.Set(reflect.Zero(dst.Type()))
+	return true
+}
+
+// convToWeak converts t to dst with the weakly typed rules of ConvOptions.WeaklyTyped.
+// It returns false if no rule is applicable, then the conversion goes on as usual.
+func (v *Value) convToWeak(s *convState, dst reflect.Value) (bool, error) {
+	src := indirect(v.getrv())
+	switch dst.Kind() {
+	case reflect.Bool:
+		b, ok := weakBool(src)
+		if !ok {
+			return false, nil
+		}
+		dst.SetBool(b)
+		return true, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		n, ok := weakNumber(src)
+		if !ok {
+			return false, nil
+		}
+		return true, New(n).convTo(s, dst)
+
+	case reflect.Array, reflect.Slice:
+		if !isScalarKind(src.Kind()) {
+			return false, nil
+		}
+		return true, New([]interface{}{src.Interface()}).convTo(s, dst)
+	}
+	return false, nil
+}
+
+// weakBool returns the bool of src, that's a bool, a number which is true if it's non-zero,
+// or a string of true/false, yes/no, on/off or 1/0 in any case.
+func weakBool(src reflect.Value) (bool, bool) {
+	switch src.Kind() {
+	case reflect.Bool:
+		return src.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return src.Int() != 0, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return src.Uint() != 0, true
+	case reflect.Float32, reflect.Float64:
+		return src.Float() != 0, true
+	case reflect.String:
+		switch strings.ToLower(strings.TrimSpace(src.String())) {
+		case "1", "t", "true", "y", "yes", "on":
+			return true, true
+		case "0", "f", "false", "n", "no", "off":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// weakNumber returns the number of src, a bool is 1 or 0,
+// and a string is parsed as Go literal which can have base prefix and underscores,
+// like "0x1f", "0o17", "0b101" or "1_000", it's decimal without prefix even if led by zeros.
+func weakNumber(src reflect.Value) (interface{}, bool) {
+	switch src.Kind() {
+	case reflect.Bool:
+		if src.Bool() {
+			return 1, true
+		}
+		return 0, true
+	case reflect.String:
+		s := strings.TrimSpace(src.String())
+		lit := decimalLiteral(s)
+		if i, err := strconv.ParseInt(lit, 0, 64); err == nil {
+			return i, true
+		}
+		if u, err := strconv.ParseUint(lit, 0, 64); err == nil {
+			return u, true
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// decimalLiteral trims the leading zeros of integer literal s without base prefix,
+// so that "010" is parsed as 10 rather than octal 8.
+func decimalLiteral(s string) string {
+	sign, digits := "", s
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		sign, digits = s[:1], s[1:]
+	}
+	if len(digits) < 2 || digits[0] != '0' || strings.IndexByte("xXoObB", digits[1]) >= 0 {
+		return s
+	}
+	if digits = strings.TrimLeft(digits, "0"); digits == "" {
+		digits = "0"
+	}
+	return sign + digits
+}
+
+// isScalarKind reports whether k is a bool, number or string.
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
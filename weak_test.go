@@ -0,0 +1,83 @@
+package value
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WeaklyTyped", func() {
+	weak := ConvOptions{WeaklyTyped: true}
+
+	Specify("string to number", func() {
+		var y struct {
+			A, B, C, D int
+			H, I, J    int
+			E          uint8
+			F          float64
+			G          int8
+		}
+		x := map[string]interface{}{
+			"a": "0x1f", "b": "0o17", "c": "0b101", "d": "1_000",
+			"e": " 255 ", "f": "0x10", "g": true,
+			"h": "010", "i": "-0755", "j": "08",
+		}
+		Expect(New(x).ConvToWithOptions(&y, weak)).Should(BeNil())
+		Expect([]int{y.A, y.B, y.C, y.D}).Should(Equal([]int{31, 15, 5, 1000}))
+		Expect(y.E).Should(Equal(uint8(255)))
+		Expect(y.F).Should(Equal(16.0))
+		Expect(y.G).Should(Equal(int8(1)))
+		Expect([]int{y.H, y.I, y.J}).Should(Equal([]int{10, -755, 8}))
+
+		var z int8
+		Expect(New("0x100").ConvToWithOptions(&z, weak)).Should(BeAssignableToTypeOf((*ErrNumOverflow)(nil)))
+		Expect(New("0x1f").ConvTo(&z)).ShouldNot(BeNil())
+	})
+	Specify("to bool", func() {
+		for _, x := range []interface{}{"yes", "ON", "1", "true", "T", 2, 0.5} {
+			var y bool
+			Expect(New(x).ConvToWithOptions(&y, weak)).Should(BeNil(), "%v", x)
+			Expect(y).Should(BeTrue(), "%v", x)
+		}
+		for _, x := range []interface{}{"no", "Off", "0", "false", 0} {
+			y := true
+			Expect(New(x).ConvToWithOptions(&y, weak)).Should(BeNil(), "%v", x)
+			Expect(y).Should(BeFalse(), "%v", x)
+		}
+		var y bool
+		Expect(New("maybe").ConvToWithOptions(&y, weak)).ShouldNot(BeNil())
+		Expect(New("yes").ConvTo(&y)).ShouldNot(BeNil())
+	})
+	Specify("scalar to one-element slice", func() {
+		var y struct {
+			Tags  []string
+			Ports [2]int
+			IP    net.IP
+		}
+		x := map[string]interface{}{"tags": "a", "ports": "0x50", "ip": "127.0.0.1"}
+		Expect(New(x).ConvToWithOptions(&y, weak)).Should(BeNil())
+		Expect(y.Tags).Should(Equal([]string{"a"}))
+		Expect(y.Ports).Should(Equal([2]int{80, 0}))
+		Expect(y.IP.String()).Should(Equal("127.0.0.1"))
+	})
+	Specify("empty string to zero", func() {
+		y := struct {
+			N int
+			B bool
+			D time.Duration
+			P *int
+			S string
+			L []string
+		}{N: 1, B: true, D: time.Second, S: "s", L: []string{"l"}}
+		x := map[string]interface{}{"n": "", "b": "", "d": "", "p": "", "s": "", "l": ""}
+		Expect(New(x).ConvToWithOptions(&y, weak)).Should(BeNil())
+		Expect(y.N).Should(BeZero())
+		Expect(y.B).Should(BeFalse())
+		Expect(y.D).Should(BeZero())
+		Expect(y.P).Should(BeNil())
+		Expect(y.S).Should(BeEmpty())
+		Expect(y.L).Should(BeNil())
+	})
+})
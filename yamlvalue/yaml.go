@@ -0,0 +1,157 @@
+// Package yamlvalue loads YAML to value.Value,
+// it's a separate module, so that the module of package value doesn't require YAML.
+package yamlvalue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	value "github.com/helloyi/go-value"
+	"gopkg.in/yaml.v3"
+)
+
+// FromYAML new a Value from the first document of YAML data.
+//
+// The mappings are decoded to map[string]interface{} with keys formatted by fmt.Sprint,
+// the merge keys "<<" are resolved, and the sequences are decoded to []interface{}.
+// The source positions of all nodes are kept, see value.NewWithPositions.
+func FromYAML(data []byte) (*value.Value, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	d := &decoder{pos: value.Positions{}, visiting: map[*yaml.Node]bool{}}
+	var x interface{}
+	if len(doc.Content) > 0 {
+		var err error
+		if x, err = d.node(doc.Content[0], ""); err != nil {
+			return nil, err
+		}
+	}
+	return value.NewWithPositions(x, d.pos), nil
+}
+
+type decoder struct {
+	pos      value.Positions
+	visiting map[*yaml.Node]bool // aliases
+}
+
+func (d *decoder) node(n *yaml.Node, path string) (interface{}, error) {
+	d.pos[path] = value.Position{Line: n.Line, Column: n.Column}
+
+	switch n.Kind {
+	case yaml.AliasNode:
+		if d.visiting[n.Alias] {
+			return nil, fmt.Errorf("yaml: line %d: cyclic alias %q", n.Line, n.Value)
+		}
+		d.visiting[n.Alias] = true
+		defer delete(d.visiting, n.Alias)
+		x, err := d.node(n.Alias, path)
+		d.pos[path] = value.Position{Line: n.Line, Column: n.Column}
+		return x, err
+
+	case yaml.ScalarNode:
+		var x interface{}
+		if err := n.Decode(&x); err != nil {
+			return nil, err
+		}
+		return x, nil
+
+	case yaml.SequenceNode:
+		s := make([]interface{}, len(n.Content))
+		for i, c := range n.Content {
+			x, err := d.node(c, path+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return nil, err
+			}
+			s[i] = x
+		}
+		return s, nil
+
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(n.Content)/2)
+		var merges []*yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			kn, vn := n.Content[i], n.Content[i+1]
+			if kn.Kind == yaml.ScalarNode && kn.ShortTag() == "!!merge" {
+				merges = append(merges, vn)
+				continue
+			}
+			key, err := d.key(kn)
+			if err != nil {
+				return nil, err
+			}
+			x, err := d.node(vn, value.PositionKey(path, key))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = x
+		}
+		for _, mn := range merges {
+			if err := d.merge(m, mn, path); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+
+	default:
+		return nil, fmt.Errorf("yaml: line %d: unsupported node %v", n.Line, n.Kind)
+	}
+}
+
+// key returns the mapping key of scalar node n formatted by fmt.Sprint.
+func (d *decoder) key(n *yaml.Node) (string, error) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("yaml: line %d: non-scalar mapping key", n.Line)
+	}
+	var k interface{}
+	if err := n.Decode(&k); err != nil {
+		return "", err
+	}
+	if k == nil {
+		return n.Value, nil
+	}
+	return fmt.Sprint(k), nil
+}
+
+// merge merges the mapping or sequence of mappings n to m, the existing keys of m take precedence.
+func (d *decoder) merge(m map[string]interface{}, n *yaml.Node, path string) error {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	srcs := []*yaml.Node{n}
+	if n.Kind == yaml.SequenceNode {
+		srcs = n.Content
+	}
+	for _, src := range srcs {
+		if src.Kind == yaml.AliasNode {
+			src = src.Alias
+		}
+		if src.Kind != yaml.MappingNode {
+			return fmt.Errorf("yaml: line %d: map merge requires map or sequence of maps", src.Line)
+		}
+		sub := &decoder{pos: value.Positions{}, visiting: d.visiting}
+		x, err := sub.node(src, "")
+		if err != nil {
+			return err
+		}
+		for key, val := range x.(map[string]interface{}) {
+			if _, ok := m[key]; ok {
+				continue
+			}
+			m[key] = val
+			k := value.PositionKey("", key)
+			for p, pos := range sub.pos {
+				if p == k || strings.HasPrefix(p, k) && (p[len(k)] == '.' || p[len(k)] == '[') {
+					d.pos[value.PositionKey(path, key)+p[len(k):]] = pos
+				}
+			}
+		}
+	}
+	return nil
+}
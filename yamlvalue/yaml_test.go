@@ -0,0 +1,97 @@
+package yamlvalue
+
+import (
+	"errors"
+	"time"
+
+	value "github.com/helloyi/go-value"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FromYAML", func() {
+	data := []byte(`
+defaults: &defaults
+  timeout: 30s
+  retries: 3
+servers:
+  - name: a
+    port: 80
+  - <<: *defaults
+    name: b
+    port: eighty
+    retries: 5
+1: one
+true: false
+`)
+
+	Specify("keys are strings", func() {
+		v, err := FromYAML(data)
+		Expect(err).Should(BeNil())
+		Expect(v.MustGetPath("1").MustString()).Should(Equal("one"))
+		Expect(v.MustGetPath("true").MustBool()).Should(BeFalse())
+		Expect(v.MustGetPath("servers[1]").Interface()).Should(Equal(map[string]interface{}{
+			"name": "b", "port": "eighty", "retries": 5, "timeout": "30s",
+		}))
+	})
+	Specify("positions", func() {
+		v, err := FromYAML(data)
+		Expect(err).Should(BeNil())
+		pos := func(v *value.Value, path string) value.Position {
+			p, ok := v.Position(path)
+			Expect(ok).Should(BeTrue(), path)
+			return p
+		}
+		Expect(pos(v, "servers[0].port")).Should(Equal(value.Position{Line: 7, Column: 11}))
+		Expect(pos(v, "servers[1].timeout")).Should(Equal(value.Position{Line: 3, Column: 12}))
+		Expect(pos(v, "servers[1].retries")).Should(Equal(value.Position{Line: 11, Column: 14}))
+		Expect(pos(v.MustGetPath("servers.1"), "name")).Should(Equal(value.Position{Line: 9, Column: 11}))
+		Expect(pos(v, "servers[0].x")).Should(Equal(value.Position{Line: 6, Column: 5}))
+
+		_, ok := value.New(1).Position("")
+		Expect(ok).Should(BeFalse())
+	})
+	Specify("positions of dotted keys", func() {
+		v, err := FromYAML([]byte("a.b: x\na:\n  b: y\n"))
+		Expect(err).Should(BeNil())
+		var y struct {
+			AB int `value:"a.b"`
+			A  struct{ B int }
+		}
+		var got []value.Position
+		for _, err := range v.ConvTo(&y).(*value.ErrMulti).Errs {
+			got = append(got, *err.(*value.ErrPath).Pos)
+		}
+		Expect(got).Should(ConsistOf(value.Position{Line: 1, Column: 6}, value.Position{Line: 3, Column: 6}))
+	})
+	Specify("positions in ConvTo errors", func() {
+		var y struct {
+			Servers []struct {
+				Name    string
+				Port    int
+				Timeout time.Duration
+			}
+		}
+		v, err := FromYAML(data)
+		Expect(err).Should(BeNil())
+		err = v.ConvTo(&y)
+		var e *value.ErrPath
+		Expect(errors.As(err, &e)).Should(BeTrue())
+		Expect(e.Path).Should(Equal("servers[1].port"))
+		Expect(e.Pos).Should(Equal(&value.Position{Line: 10, Column: 11}))
+		Expect(e.Error()).Should(ContainSubstring(`"servers[1].port" (line 10, column 11)`))
+		Expect(y.Servers[1].Timeout).Should(Equal(30 * time.Second))
+	})
+	Specify("failed", func() {
+		_, err := FromYAML([]byte("a: [1"))
+		Expect(err).ShouldNot(BeNil())
+		_, err = FromYAML([]byte("? [a]\n: 1"))
+		Expect(err).ShouldNot(BeNil())
+		_, err = FromYAML([]byte("a:\n  <<: 1"))
+		Expect(err).ShouldNot(BeNil())
+
+		v, err := FromYAML(nil)
+		Expect(err).Should(BeNil())
+		Expect(v.Interface()).Should(BeNil())
+	})
+})
@@ -0,0 +1,13 @@
+package yamlvalue
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestYAMLValue(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "YAMLValue Suite")
+}